@@ -32,18 +32,26 @@ const (
 	Mark_MARK_X Mark = 1
 	// O (Nought).
 	Mark_MARK_O Mark = 2
+	// Triangle, for the third player in matches with more than two seats.
+	Mark_MARK_TRIANGLE Mark = 3
+	// Square, for the fourth player in matches with four seats.
+	Mark_MARK_SQUARE Mark = 4
 )
 
 var Mark_name = map[int32]string{
 	0: "MARK_UNSPECIFIED",
 	1: "MARK_X",
 	2: "MARK_O",
+	3: "MARK_TRIANGLE",
+	4: "MARK_SQUARE",
 }
 
 var Mark_value = map[string]int32{
 	"MARK_UNSPECIFIED": 0,
 	"MARK_X":           1,
 	"MARK_O":           2,
+	"MARK_TRIANGLE":    3,
+	"MARK_SQUARE":      4,
 }
 
 func (x Mark) String() string {
@@ -70,24 +78,77 @@ const (
 	OpCode_OPCODE_MOVE OpCode = 4
 	// Move was rejected.
 	OpCode_OPCODE_REJECTED OpCode = 5
+	// A player has won the series and the match is over.
+	OpCode_OPCODE_SERIES_DONE OpCode = 6
+	// A player concedes the current round.
+	OpCode_OPCODE_RESIGN OpCode = 7
+	// A player offers their opponent a draw, relayed by the server to everyone in the match.
+	OpCode_OPCODE_DRAW_OFFER OpCode = 8
+	// A player answers a draw offer. Declines are relayed by the server to everyone in the match.
+	OpCode_OPCODE_DRAW_RESPONSE OpCode = 9
+	// A player wants to play another round against the same opponent, relayed by the server to everyone in the match.
+	OpCode_OPCODE_REMATCH_ACCEPT OpCode = 10
+	// A player does not want another round, relayed by the server to everyone in the match. The player leaves the match.
+	OpCode_OPCODE_REMATCH_DECLINE OpCode = 11
+	// A player dropped out of the game in progress. The turn timer is paused until they return or run out of time to.
+	OpCode_OPCODE_OPPONENT_DISCONNECTED OpCode = 12
+	// A disconnected player returned to the game in progress, and the turn timer resumes.
+	OpCode_OPCODE_OPPONENT_RECONNECTED OpCode = 13
+	// A client is sending messages faster than the server accepts them. Messages over the limit are dropped, and clients
+	// that carry on are removed from the match.
+	OpCode_OPCODE_INPUT_WARNING OpCode = 14
+	// Statistics for the whole match, sent to everyone still in it as it closes.
+	OpCode_OPCODE_MATCH_SUMMARY OpCode = 15
+	// A client asks for the full current state of the match, for example if it suspects it missed an update.
+	OpCode_OPCODE_SYNC_REQUEST OpCode = 16
+	// The full current state of the match, sent to a single client as it joins or when it asks for it.
+	OpCode_OPCODE_SNAPSHOT OpCode = 17
+	// A player is out of the game in progress, in matches with more than two seats.
+	OpCode_OPCODE_PLAYER_ELIMINATED OpCode = 18
 )
 
 var OpCode_name = map[int32]string{
-	0: "OPCODE_UNSPECIFIED",
-	1: "OPCODE_START",
-	2: "OPCODE_UPDATE",
-	3: "OPCODE_DONE",
-	4: "OPCODE_MOVE",
-	5: "OPCODE_REJECTED",
+	0:  "OPCODE_UNSPECIFIED",
+	1:  "OPCODE_START",
+	2:  "OPCODE_UPDATE",
+	3:  "OPCODE_DONE",
+	4:  "OPCODE_MOVE",
+	5:  "OPCODE_REJECTED",
+	6:  "OPCODE_SERIES_DONE",
+	7:  "OPCODE_RESIGN",
+	8:  "OPCODE_DRAW_OFFER",
+	9:  "OPCODE_DRAW_RESPONSE",
+	10: "OPCODE_REMATCH_ACCEPT",
+	11: "OPCODE_REMATCH_DECLINE",
+	12: "OPCODE_OPPONENT_DISCONNECTED",
+	13: "OPCODE_OPPONENT_RECONNECTED",
+	14: "OPCODE_INPUT_WARNING",
+	15: "OPCODE_MATCH_SUMMARY",
+	16: "OPCODE_SYNC_REQUEST",
+	17: "OPCODE_SNAPSHOT",
+	18: "OPCODE_PLAYER_ELIMINATED",
 }
 
 var OpCode_value = map[string]int32{
-	"OPCODE_UNSPECIFIED": 0,
-	"OPCODE_START":       1,
-	"OPCODE_UPDATE":      2,
-	"OPCODE_DONE":        3,
-	"OPCODE_MOVE":        4,
-	"OPCODE_REJECTED":    5,
+	"OPCODE_UNSPECIFIED":           0,
+	"OPCODE_START":                 1,
+	"OPCODE_UPDATE":                2,
+	"OPCODE_DONE":                  3,
+	"OPCODE_MOVE":                  4,
+	"OPCODE_REJECTED":              5,
+	"OPCODE_SERIES_DONE":           6,
+	"OPCODE_RESIGN":                7,
+	"OPCODE_DRAW_OFFER":            8,
+	"OPCODE_DRAW_RESPONSE":         9,
+	"OPCODE_REMATCH_ACCEPT":        10,
+	"OPCODE_REMATCH_DECLINE":       11,
+	"OPCODE_OPPONENT_DISCONNECTED": 12,
+	"OPCODE_OPPONENT_RECONNECTED":  13,
+	"OPCODE_INPUT_WARNING":         14,
+	"OPCODE_MATCH_SUMMARY":         15,
+	"OPCODE_SYNC_REQUEST":          16,
+	"OPCODE_SNAPSHOT":              17,
+	"OPCODE_PLAYER_ELIMINATED":     18,
 }
 
 func (x OpCode) String() string {
@@ -98,6 +159,173 @@ func (OpCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
 }
 
+// Why a game round ended.
+type DoneReason int32
+
+const (
+	// No reason specified. Unused.
+	DoneReason_DONE_REASON_UNSPECIFIED DoneReason = 0
+	// The winner completed a line.
+	DoneReason_DONE_REASON_WIN_LINE DoneReason = 1
+	// The board filled up with no winner.
+	DoneReason_DONE_REASON_BOARD_FULL DoneReason = 2
+	// A player ran out of time to make their move.
+	DoneReason_DONE_REASON_TIMEOUT DoneReason = 3
+	// A player resigned.
+	DoneReason_DONE_REASON_RESIGN DoneReason = 4
+	// Both players agreed to a draw.
+	DoneReason_DONE_REASON_AGREED_DRAW DoneReason = 5
+	// A player left the match and did not make their move in time.
+	DoneReason_DONE_REASON_OPPONENT_LEFT DoneReason = 6
+	// A player dropped out of the game and did not reconnect in time.
+	DoneReason_DONE_REASON_DISCONNECT DoneReason = 7
+	// The loser completed a line, in variants where that loses the game.
+	DoneReason_DONE_REASON_LOSING_LINE DoneReason = 8
+	// Every player dropped out of the game and none reconnected in time, so nobody wins.
+	DoneReason_DONE_REASON_ABANDONED DoneReason = 9
+)
+
+var DoneReason_name = map[int32]string{
+	0: "DONE_REASON_UNSPECIFIED",
+	1: "DONE_REASON_WIN_LINE",
+	2: "DONE_REASON_BOARD_FULL",
+	3: "DONE_REASON_TIMEOUT",
+	4: "DONE_REASON_RESIGN",
+	5: "DONE_REASON_AGREED_DRAW",
+	6: "DONE_REASON_OPPONENT_LEFT",
+	7: "DONE_REASON_DISCONNECT",
+	8: "DONE_REASON_LOSING_LINE",
+	9: "DONE_REASON_ABANDONED",
+}
+
+var DoneReason_value = map[string]int32{
+	"DONE_REASON_UNSPECIFIED":   0,
+	"DONE_REASON_WIN_LINE":      1,
+	"DONE_REASON_BOARD_FULL":    2,
+	"DONE_REASON_TIMEOUT":       3,
+	"DONE_REASON_RESIGN":        4,
+	"DONE_REASON_AGREED_DRAW":   5,
+	"DONE_REASON_OPPONENT_LEFT": 6,
+	"DONE_REASON_DISCONNECT":    7,
+	"DONE_REASON_LOSING_LINE":   8,
+	"DONE_REASON_ABANDONED":     9,
+}
+
+func (x DoneReason) String() string {
+	return proto.EnumName(DoneReason_name, int32(x))
+}
+
+func (DoneReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+}
+
+// How well a server-side bot opponent plays.
+type BotDifficulty int32
+
+const (
+	// No bot, only wait for other players.
+	BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED BotDifficulty = 0
+	// Plays a random free position.
+	BotDifficulty_BOT_DIFFICULTY_RANDOM BotDifficulty = 1
+	// Takes winning moves, blocks the opponent's winning moves, and otherwise builds towards open lines.
+	BotDifficulty_BOT_DIFFICULTY_HEURISTIC BotDifficulty = 2
+	// Searches the full game tree and never loses on boards small enough to search.
+	BotDifficulty_BOT_DIFFICULTY_PERFECT BotDifficulty = 3
+)
+
+var BotDifficulty_name = map[int32]string{
+	0: "BOT_DIFFICULTY_UNSPECIFIED",
+	1: "BOT_DIFFICULTY_RANDOM",
+	2: "BOT_DIFFICULTY_HEURISTIC",
+	3: "BOT_DIFFICULTY_PERFECT",
+}
+
+var BotDifficulty_value = map[string]int32{
+	"BOT_DIFFICULTY_UNSPECIFIED": 0,
+	"BOT_DIFFICULTY_RANDOM":      1,
+	"BOT_DIFFICULTY_HEURISTIC":   2,
+	"BOT_DIFFICULTY_PERFECT":     3,
+}
+
+func (x BotDifficulty) String() string {
+	return proto.EnumName(BotDifficulty_name, int32(x))
+}
+
+func (BotDifficulty) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
+// How the player who moves first is chosen at the start of each game round.
+type FirstMovePolicy int32
+
+const (
+	// Same as random.
+	FirstMovePolicy_FIRST_MOVE_POLICY_UNSPECIFIED FirstMovePolicy = 0
+	// Draw the first player at random, using the match seed.
+	FirstMovePolicy_FIRST_MOVE_POLICY_RANDOM FirstMovePolicy = 1
+	// Players take turns to move first. The first round is drawn at random.
+	FirstMovePolicy_FIRST_MOVE_POLICY_ALTERNATE FirstMovePolicy = 2
+	// The loser of the previous round moves first. The first round is drawn at random, and rounds after a draw alternate.
+	FirstMovePolicy_FIRST_MOVE_POLICY_LOSER_FIRST FirstMovePolicy = 3
+)
+
+var FirstMovePolicy_name = map[int32]string{
+	0: "FIRST_MOVE_POLICY_UNSPECIFIED",
+	1: "FIRST_MOVE_POLICY_RANDOM",
+	2: "FIRST_MOVE_POLICY_ALTERNATE",
+	3: "FIRST_MOVE_POLICY_LOSER_FIRST",
+}
+
+var FirstMovePolicy_value = map[string]int32{
+	"FIRST_MOVE_POLICY_UNSPECIFIED": 0,
+	"FIRST_MOVE_POLICY_RANDOM":      1,
+	"FIRST_MOVE_POLICY_ALTERNATE":   2,
+	"FIRST_MOVE_POLICY_LOSER_FIRST": 3,
+}
+
+func (x FirstMovePolicy) String() string {
+	return proto.EnumName(FirstMovePolicy_name, int32(x))
+}
+
+func (FirstMovePolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+// Why a player was chosen to move first.
+type FirstMoveReason int32
+
+const (
+	FirstMoveReason_FIRST_MOVE_REASON_UNSPECIFIED FirstMoveReason = 0
+	// Drawn at random, using the match seed.
+	FirstMoveReason_FIRST_MOVE_REASON_RANDOM FirstMoveReason = 1
+	// The player moved second in the previous round.
+	FirstMoveReason_FIRST_MOVE_REASON_ALTERNATE FirstMoveReason = 2
+	// The player lost the previous round.
+	FirstMoveReason_FIRST_MOVE_REASON_LOSER FirstMoveReason = 3
+)
+
+var FirstMoveReason_name = map[int32]string{
+	0: "FIRST_MOVE_REASON_UNSPECIFIED",
+	1: "FIRST_MOVE_REASON_RANDOM",
+	2: "FIRST_MOVE_REASON_ALTERNATE",
+	3: "FIRST_MOVE_REASON_LOSER",
+}
+
+var FirstMoveReason_value = map[string]int32{
+	"FIRST_MOVE_REASON_UNSPECIFIED": 0,
+	"FIRST_MOVE_REASON_RANDOM":      1,
+	"FIRST_MOVE_REASON_ALTERNATE":   2,
+	"FIRST_MOVE_REASON_LOSER":       3,
+}
+
+func (x FirstMoveReason) String() string {
+	return proto.EnumName(FirstMoveReason_name, int32(x))
+}
+
+func (FirstMoveReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+
 // Message data sent by server to clients representing a new game round starting.
 type Start struct {
 	// The current state of the board.
@@ -107,7 +335,26 @@ type Start struct {
 	// Whose turn it is to play.
 	Mark Mark `protobuf:"varint,3,opt,name=mark,proto3,enum=api.Mark" json:"mark,omitempty"`
 	// The deadline time by which the player must submit their move, or forfeit.
-	Deadline             int64    `protobuf:"varint,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	Deadline int64 `protobuf:"varint,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// The number of cells along each side of the square board. 9 in ultimate tic-tac-toe, where the board lists the
+	// cells sub-board by sub-board rather than row by row, as laid out in UltimateBoard.
+	BoardSize int32 `protobuf:"varint,5,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win.
+	WinLength int32 `protobuf:"varint,6,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Games won so far in the series by each player's user ID, if this is a series.
+	SeriesScore map[string]int32 `protobuf:"bytes,7,rep,name=series_score,json=seriesScore,proto3" json:"series_score,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of games the series is played over, or 0 if games continue indefinitely.
+	SeriesLength int32 `protobuf:"varint,8,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// Why the player with the first move was chosen.
+	FirstMoveReason FirstMoveReason `protobuf:"varint,9,opt,name=first_move_reason,json=firstMoveReason,proto3,enum=api.FirstMoveReason" json:"first_move_reason,omitempty"`
+	// Each player's remaining time bank in milliseconds, by user ID, if the match is played with a chess clock.
+	Clocks map[string]int64 `protobuf:"bytes,10,rep,name=clocks,proto3" json:"clocks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one.
+	Sequence int64 `protobuf:"varint,11,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// The nested boards, if this is a game of ultimate tic-tac-toe.
+	Ultimate *UltimateBoard `protobuf:"bytes,12,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	// The rules the game is played by.
+	Variant              string   `protobuf:"bytes,13,opt,name=variant,proto3" json:"variant,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -166,6 +413,69 @@ func (m *Start) GetDeadline() int64 {
 	return 0
 }
 
+func (m *Start) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *Start) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *Start) GetSeriesScore() map[string]int32 {
+	if m != nil {
+		return m.SeriesScore
+	}
+	return nil
+}
+
+func (m *Start) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *Start) GetFirstMoveReason() FirstMoveReason {
+	if m != nil {
+		return m.FirstMoveReason
+	}
+	return FirstMoveReason_FIRST_MOVE_REASON_UNSPECIFIED
+}
+
+func (m *Start) GetClocks() map[string]int64 {
+	if m != nil {
+		return m.Clocks
+	}
+	return nil
+}
+
+func (m *Start) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Start) GetUltimate() *UltimateBoard {
+	if m != nil {
+		return m.Ultimate
+	}
+	return nil
+}
+
+func (m *Start) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
 // A game state update sent by the server to clients.
 type Update struct {
 	// The current state of the board.
@@ -175,10 +485,21 @@ type Update struct {
 	// The assignments of the marks to players for this round.
 	Marks map[string]Mark `protobuf:"bytes,3,rep,name=marks,proto3" json:"marks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=api.Mark"`
 	// The deadline time by which the player must submit their move, or forfeit.
-	Deadline             int64    `protobuf:"varint,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Deadline int64 `protobuf:"varint,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// The number of cells along each side of the square board. 9 in ultimate tic-tac-toe, where the board lists the
+	// cells sub-board by sub-board rather than row by row, as laid out in UltimateBoard.
+	BoardSize int32 `protobuf:"varint,5,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win.
+	WinLength int32 `protobuf:"varint,6,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Each player's remaining time bank in milliseconds, by user ID, if the match is played with a chess clock.
+	Clocks map[string]int64 `protobuf:"bytes,7,rep,name=clocks,proto3" json:"clocks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one.
+	Sequence int64 `protobuf:"varint,8,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// The nested boards, if this is a game of ultimate tic-tac-toe.
+	Ultimate             *UltimateBoard `protobuf:"bytes,9,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *Update) Reset()         { *m = Update{} }
@@ -234,6 +555,41 @@ func (m *Update) GetDeadline() int64 {
 	return 0
 }
 
+func (m *Update) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *Update) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *Update) GetClocks() map[string]int64 {
+	if m != nil {
+		return m.Clocks
+	}
+	return nil
+}
+
+func (m *Update) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Update) GetUltimate() *UltimateBoard {
+	if m != nil {
+		return m.Ultimate
+	}
+	return nil
+}
+
 // Complete game round with winner announcement.
 type Done struct {
 	// The current state of the board.
@@ -243,13 +599,32 @@ type Done struct {
 	// The winner of the game, if any. Unspecified if it's a draw.
 	Winner Mark `protobuf:"varint,4,opt,name=winner,proto3,enum=api.Mark" json:"winner,omitempty"`
 	// Winner board positions, if any. Used to display the row, column, or diagonal that won the game.
-	// May be empty if it's a draw or the winner is by forfeit.
+	// May be empty if it's a draw or the winner is by forfeit. In ultimate tic-tac-toe these are the sub-boards that
+	// won the game.
 	WinnerPositions []int32 `protobuf:"varint,5,rep,packed,name=winner_positions,json=winnerPositions,proto3" json:"winner_positions,omitempty"`
 	// Next round start time.
-	NextGameStart        int64    `protobuf:"varint,6,opt,name=next_game_start,json=nextGameStart,proto3" json:"next_game_start,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	NextGameStart int64 `protobuf:"varint,6,opt,name=next_game_start,json=nextGameStart,proto3" json:"next_game_start,omitempty"`
+	// The number of cells along each side of the square board. 9 in ultimate tic-tac-toe, where the board lists the
+	// cells sub-board by sub-board rather than row by row, as laid out in UltimateBoard.
+	BoardSize int32 `protobuf:"varint,7,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win.
+	WinLength int32 `protobuf:"varint,8,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Games won so far in the series by each player's user ID, including this one, if this is a series.
+	SeriesScore map[string]int32 `protobuf:"bytes,9,rep,name=series_score,json=seriesScore,proto3" json:"series_score,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of games the series is played over, or 0 if games continue indefinitely.
+	SeriesLength int32 `protobuf:"varint,10,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// Why the round ended.
+	Reason DoneReason `protobuf:"varint,11,opt,name=reason,proto3,enum=api.DoneReason" json:"reason,omitempty"`
+	// The deadline time by which players must accept a rematch, if the next round depends on it.
+	RematchDeadline int64 `protobuf:"varint,12,opt,name=rematch_deadline,json=rematchDeadline,proto3" json:"rematch_deadline,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one. Unset when
+	// included in a snapshot.
+	Sequence int64 `protobuf:"varint,13,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// The nested boards, if this is a game of ultimate tic-tac-toe. Winner positions are then the sub-boards that won.
+	Ultimate             *UltimateBoard `protobuf:"bytes,14,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *Done) Reset()         { *m = Done{} }
@@ -312,264 +687,3304 @@ func (m *Done) GetNextGameStart() int64 {
 	return 0
 }
 
-// A player intends to make a move.
-type Move struct {
-	// The position the player wants to place their mark in.
-	Position             int32    `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Done) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
 }
 
-func (m *Move) Reset()         { *m = Move{} }
-func (m *Move) String() string { return proto.CompactTextString(m) }
-func (*Move) ProtoMessage()    {}
-func (*Move) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+func (m *Done) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
 }
 
-func (m *Move) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Move.Unmarshal(m, b)
-}
-func (m *Move) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Move.Marshal(b, m, deterministic)
-}
-func (m *Move) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Move.Merge(m, src)
+func (m *Done) GetSeriesScore() map[string]int32 {
+	if m != nil {
+		return m.SeriesScore
+	}
+	return nil
 }
-func (m *Move) XXX_Size() int {
-	return xxx_messageInfo_Move.Size(m)
+
+func (m *Done) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
 }
-func (m *Move) XXX_DiscardUnknown() {
-	xxx_messageInfo_Move.DiscardUnknown(m)
+
+func (m *Done) GetReason() DoneReason {
+	if m != nil {
+		return m.Reason
+	}
+	return DoneReason_DONE_REASON_UNSPECIFIED
 }
 
-var xxx_messageInfo_Move proto.InternalMessageInfo
+func (m *Done) GetRematchDeadline() int64 {
+	if m != nil {
+		return m.RematchDeadline
+	}
+	return 0
+}
 
-func (m *Move) GetPosition() int32 {
+func (m *Done) GetSequence() int64 {
 	if m != nil {
-		return m.Position
+		return m.Sequence
 	}
 	return 0
 }
 
-// Payload for an RPC request to find a match.
-type RpcFindMatchRequest struct {
-	// User can choose a fast or normal speed match.
-	Fast                 bool     `protobuf:"varint,1,opt,name=fast,proto3" json:"fast,omitempty"`
+func (m *Done) GetUltimate() *UltimateBoard {
+	if m != nil {
+		return m.Ultimate
+	}
+	return nil
+}
+
+// The full authoritative state of the match.
+type Snapshot struct {
+	// The sequence number of the most recent state message, any older messages are out of date.
+	Sequence int64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Whether a game is in progress.
+	Playing bool `protobuf:"varint,2,opt,name=playing,proto3" json:"playing,omitempty"`
+	// The current state of the board, if a game is in progress.
+	Board []Mark `protobuf:"varint,3,rep,packed,name=board,proto3,enum=api.Mark" json:"board,omitempty"`
+	// The assignments of the marks to players for the game in progress.
+	Marks map[string]Mark `protobuf:"bytes,4,rep,name=marks,proto3" json:"marks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=api.Mark"`
+	// Whose turn it is to play.
+	Mark Mark `protobuf:"varint,5,opt,name=mark,proto3,enum=api.Mark" json:"mark,omitempty"`
+	// The deadline time by which the player must submit their move, or forfeit.
+	Deadline int64 `protobuf:"varint,6,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// The number of cells along each side of the square board. 9 in ultimate tic-tac-toe, where the board lists the
+	// cells sub-board by sub-board rather than row by row, as laid out in UltimateBoard.
+	BoardSize int32 `protobuf:"varint,7,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win.
+	WinLength int32 `protobuf:"varint,8,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Each player's remaining time bank in milliseconds, by user ID, if the match is played with a chess clock.
+	Clocks map[string]int64 `protobuf:"bytes,9,rep,name=clocks,proto3" json:"clocks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Games won so far in the series by each player's user ID, if this is a series.
+	SeriesScore map[string]int32 `protobuf:"bytes,10,rep,name=series_score,json=seriesScore,proto3" json:"series_score,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of games the series is played over, or 0 if games continue indefinitely.
+	SeriesLength int32 `protobuf:"varint,11,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// The most recently completed game, if no game is in progress.
+	Done *Done `protobuf:"bytes,12,opt,name=done,proto3" json:"done,omitempty"`
+	// The nested boards, if a game of ultimate tic-tac-toe is in progress.
+	Ultimate *UltimateBoard `protobuf:"bytes,13,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	// The rules the match is played by.
+	Variant string `protobuf:"bytes,14,opt,name=variant,proto3" json:"variant,omitempty"`
+	// The user IDs of players eliminated from the game in progress, in matches with more than two seats.
+	Eliminated           []string `protobuf:"bytes,15,rep,name=eliminated,proto3" json:"eliminated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RpcFindMatchRequest) Reset()         { *m = RpcFindMatchRequest{} }
-func (m *RpcFindMatchRequest) String() string { return proto.CompactTextString(m) }
-func (*RpcFindMatchRequest) ProtoMessage()    {}
-func (*RpcFindMatchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+func (m *Snapshot) Reset()         { *m = Snapshot{} }
+func (m *Snapshot) String() string { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()    {}
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
 }
 
-func (m *RpcFindMatchRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RpcFindMatchRequest.Unmarshal(m, b)
+func (m *Snapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Snapshot.Unmarshal(m, b)
 }
-func (m *RpcFindMatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RpcFindMatchRequest.Marshal(b, m, deterministic)
+func (m *Snapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Snapshot.Marshal(b, m, deterministic)
 }
-func (m *RpcFindMatchRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RpcFindMatchRequest.Merge(m, src)
+func (m *Snapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Snapshot.Merge(m, src)
 }
-func (m *RpcFindMatchRequest) XXX_Size() int {
-	return xxx_messageInfo_RpcFindMatchRequest.Size(m)
+func (m *Snapshot) XXX_Size() int {
+	return xxx_messageInfo_Snapshot.Size(m)
 }
-func (m *RpcFindMatchRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_RpcFindMatchRequest.DiscardUnknown(m)
+func (m *Snapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Snapshot.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RpcFindMatchRequest proto.InternalMessageInfo
+var xxx_messageInfo_Snapshot proto.InternalMessageInfo
 
-func (m *RpcFindMatchRequest) GetFast() bool {
+func (m *Snapshot) GetSequence() int64 {
 	if m != nil {
-		return m.Fast
+		return m.Sequence
 	}
-	return false
+	return 0
 }
 
-// Payload for an RPC response containing match IDs the user can join.
-type RpcFindMatchResponse struct {
-	// One or more matches that fit the user's request.
-	MatchIds             []string `protobuf:"bytes,1,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Snapshot) GetPlaying() bool {
+	if m != nil {
+		return m.Playing
+	}
+	return false
 }
 
-func (m *RpcFindMatchResponse) Reset()         { *m = RpcFindMatchResponse{} }
-func (m *RpcFindMatchResponse) String() string { return proto.CompactTextString(m) }
-func (*RpcFindMatchResponse) ProtoMessage()    {}
-func (*RpcFindMatchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+func (m *Snapshot) GetBoard() []Mark {
+	if m != nil {
+		return m.Board
+	}
+	return nil
 }
 
-func (m *RpcFindMatchResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RpcFindMatchResponse.Unmarshal(m, b)
+func (m *Snapshot) GetMarks() map[string]Mark {
+	if m != nil {
+		return m.Marks
+	}
+	return nil
 }
-func (m *RpcFindMatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RpcFindMatchResponse.Marshal(b, m, deterministic)
+
+func (m *Snapshot) GetMark() Mark {
+	if m != nil {
+		return m.Mark
+	}
+	return Mark_MARK_UNSPECIFIED
 }
-func (m *RpcFindMatchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RpcFindMatchResponse.Merge(m, src)
+
+func (m *Snapshot) GetDeadline() int64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
 }
-func (m *RpcFindMatchResponse) XXX_Size() int {
-	return xxx_messageInfo_RpcFindMatchResponse.Size(m)
+
+func (m *Snapshot) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
 }
-func (m *RpcFindMatchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RpcFindMatchResponse.DiscardUnknown(m)
+
+func (m *Snapshot) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
 }
 
-var xxx_messageInfo_RpcFindMatchResponse proto.InternalMessageInfo
+func (m *Snapshot) GetClocks() map[string]int64 {
+	if m != nil {
+		return m.Clocks
+	}
+	return nil
+}
 
-func (m *RpcFindMatchResponse) GetMatchIds() []string {
+func (m *Snapshot) GetSeriesScore() map[string]int32 {
 	if m != nil {
-		return m.MatchIds
+		return m.SeriesScore
 	}
 	return nil
 }
 
-// Payload for an RPC request to get a match.
+func (m *Snapshot) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *Snapshot) GetDone() *Done {
+	if m != nil {
+		return m.Done
+	}
+	return nil
+}
+
+func (m *Snapshot) GetUltimate() *UltimateBoard {
+	if m != nil {
+		return m.Ultimate
+	}
+	return nil
+}
+
+func (m *Snapshot) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *Snapshot) GetEliminated() []string {
+	if m != nil {
+		return m.Eliminated
+	}
+	return nil
+}
+
+// The board of a game of ultimate tic-tac-toe: a 3x3 grid of 3x3 sub-boards. Winning a sub-board claims its place
+// in the grid, and a line of claimed sub-boards wins the game. The cell a player picks decides which sub-board their
+// opponent must play in next.
+//
+// Board positions run sub-board by sub-board: position = sub_board * 9 + cell, with both the sub-board and the cell
+// within it counted row by row from the top left. The flat board in Start, Update, Done and Snapshot, and
+// Move.position, all use this layout, so position 10 is the second cell of the second sub-board, not the second cell
+// of the grid's second row.
+type UltimateBoard struct {
+	// The nine sub-boards, counted row by row from the top left.
+	SubBoards []*SubBoard `protobuf:"bytes,1,rep,name=sub_boards,json=subBoards,proto3" json:"sub_boards,omitempty"`
+	// The sub-board the next move must be played in, or -1 if the player may choose any sub-board still in play.
+	ActiveSubBoard       int32    `protobuf:"varint,2,opt,name=active_sub_board,json=activeSubBoard,proto3" json:"active_sub_board,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UltimateBoard) Reset()         { *m = UltimateBoard{} }
+func (m *UltimateBoard) String() string { return proto.CompactTextString(m) }
+func (*UltimateBoard) ProtoMessage()    {}
+func (*UltimateBoard) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+func (m *UltimateBoard) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UltimateBoard.Unmarshal(m, b)
+}
+func (m *UltimateBoard) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UltimateBoard.Marshal(b, m, deterministic)
+}
+func (m *UltimateBoard) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UltimateBoard.Merge(m, src)
+}
+func (m *UltimateBoard) XXX_Size() int {
+	return xxx_messageInfo_UltimateBoard.Size(m)
+}
+func (m *UltimateBoard) XXX_DiscardUnknown() {
+	xxx_messageInfo_UltimateBoard.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UltimateBoard proto.InternalMessageInfo
+
+func (m *UltimateBoard) GetSubBoards() []*SubBoard {
+	if m != nil {
+		return m.SubBoards
+	}
+	return nil
+}
+
+func (m *UltimateBoard) GetActiveSubBoard() int32 {
+	if m != nil {
+		return m.ActiveSubBoard
+	}
+	return 0
+}
+
+// One of the sub-boards of a game of ultimate tic-tac-toe.
+type SubBoard struct {
+	// The nine cells of the sub-board, counted row by row from the top left.
+	Cells []Mark `protobuf:"varint,1,rep,packed,name=cells,proto3,enum=api.Mark" json:"cells,omitempty"`
+	// The player who claimed the sub-board, if any.
+	Winner Mark `protobuf:"varint,2,opt,name=winner,proto3,enum=api.Mark" json:"winner,omitempty"`
+	// Whether the sub-board has been won or filled up, so no more moves can be played in it.
+	Closed               bool     `protobuf:"varint,3,opt,name=closed,proto3" json:"closed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubBoard) Reset()         { *m = SubBoard{} }
+func (m *SubBoard) String() string { return proto.CompactTextString(m) }
+func (*SubBoard) ProtoMessage()    {}
+func (*SubBoard) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+
+func (m *SubBoard) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubBoard.Unmarshal(m, b)
+}
+func (m *SubBoard) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubBoard.Marshal(b, m, deterministic)
+}
+func (m *SubBoard) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubBoard.Merge(m, src)
+}
+func (m *SubBoard) XXX_Size() int {
+	return xxx_messageInfo_SubBoard.Size(m)
+}
+func (m *SubBoard) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubBoard.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubBoard proto.InternalMessageInfo
+
+func (m *SubBoard) GetCells() []Mark {
+	if m != nil {
+		return m.Cells
+	}
+	return nil
+}
+
+func (m *SubBoard) GetWinner() Mark {
+	if m != nil {
+		return m.Winner
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *SubBoard) GetClosed() bool {
+	if m != nil {
+		return m.Closed
+	}
+	return false
+}
+
+// A player has won a majority of the games in a series. The match closes shortly after.
+type SeriesDone struct {
+	// The user ID of the player who won the series.
+	Winner string `protobuf:"bytes,1,opt,name=winner,proto3" json:"winner,omitempty"`
+	// Games won in the series by each player's user ID.
+	SeriesScore map[string]int32 `protobuf:"bytes,2,rep,name=series_score,json=seriesScore,proto3" json:"series_score,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of games the series was played over.
+	SeriesLength int32 `protobuf:"varint,3,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one.
+	Sequence             int64    `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeriesDone) Reset()         { *m = SeriesDone{} }
+func (m *SeriesDone) String() string { return proto.CompactTextString(m) }
+func (*SeriesDone) ProtoMessage()    {}
+func (*SeriesDone) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *SeriesDone) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeriesDone.Unmarshal(m, b)
+}
+func (m *SeriesDone) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeriesDone.Marshal(b, m, deterministic)
+}
+func (m *SeriesDone) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesDone.Merge(m, src)
+}
+func (m *SeriesDone) XXX_Size() int {
+	return xxx_messageInfo_SeriesDone.Size(m)
+}
+func (m *SeriesDone) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesDone.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesDone proto.InternalMessageInfo
+
+func (m *SeriesDone) GetWinner() string {
+	if m != nil {
+		return m.Winner
+	}
+	return ""
+}
+
+func (m *SeriesDone) GetSeriesScore() map[string]int32 {
+	if m != nil {
+		return m.SeriesScore
+	}
+	return nil
+}
+
+func (m *SeriesDone) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *SeriesDone) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A player concedes the current round. Sent by clients with no fields set.
+type Resign struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Resign) Reset()         { *m = Resign{} }
+func (m *Resign) String() string { return proto.CompactTextString(m) }
+func (*Resign) ProtoMessage()    {}
+func (*Resign) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+
+func (m *Resign) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Resign.Unmarshal(m, b)
+}
+func (m *Resign) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Resign.Marshal(b, m, deterministic)
+}
+func (m *Resign) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Resign.Merge(m, src)
+}
+func (m *Resign) XXX_Size() int {
+	return xxx_messageInfo_Resign.Size(m)
+}
+func (m *Resign) XXX_DiscardUnknown() {
+	xxx_messageInfo_Resign.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Resign proto.InternalMessageInfo
+
+// A draw offer for the current round.
+type DrawOffer struct {
+	// The user ID of the player offering the draw. Set by the server when relaying the offer.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one. Set by the server.
+	Sequence             int64    `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DrawOffer) Reset()         { *m = DrawOffer{} }
+func (m *DrawOffer) String() string { return proto.CompactTextString(m) }
+func (*DrawOffer) ProtoMessage()    {}
+func (*DrawOffer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *DrawOffer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DrawOffer.Unmarshal(m, b)
+}
+func (m *DrawOffer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DrawOffer.Marshal(b, m, deterministic)
+}
+func (m *DrawOffer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DrawOffer.Merge(m, src)
+}
+func (m *DrawOffer) XXX_Size() int {
+	return xxx_messageInfo_DrawOffer.Size(m)
+}
+func (m *DrawOffer) XXX_DiscardUnknown() {
+	xxx_messageInfo_DrawOffer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DrawOffer proto.InternalMessageInfo
+
+func (m *DrawOffer) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *DrawOffer) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A player's answer to a pending draw offer.
+type DrawResponse struct {
+	// True to accept the draw and end the round as a tie.
+	Accept bool `protobuf:"varint,1,opt,name=accept,proto3" json:"accept,omitempty"`
+	// The user ID of the player answering. Set by the server when relaying a decline.
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one. Set by the server.
+	Sequence             int64    `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DrawResponse) Reset()         { *m = DrawResponse{} }
+func (m *DrawResponse) String() string { return proto.CompactTextString(m) }
+func (*DrawResponse) ProtoMessage()    {}
+func (*DrawResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *DrawResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DrawResponse.Unmarshal(m, b)
+}
+func (m *DrawResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DrawResponse.Marshal(b, m, deterministic)
+}
+func (m *DrawResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DrawResponse.Merge(m, src)
+}
+func (m *DrawResponse) XXX_Size() int {
+	return xxx_messageInfo_DrawResponse.Size(m)
+}
+func (m *DrawResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DrawResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DrawResponse proto.InternalMessageInfo
+
+func (m *DrawResponse) GetAccept() bool {
+	if m != nil {
+		return m.Accept
+	}
+	return false
+}
+
+func (m *DrawResponse) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *DrawResponse) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A player's rematch vote.
+type Rematch struct {
+	// The user ID of the player voting. Set by the server when relaying the vote.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Set by the server when declining on behalf of a player who did not vote before the rematch deadline.
+	Timeout bool `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one. Set by the server.
+	Sequence             int64    `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Rematch) Reset()         { *m = Rematch{} }
+func (m *Rematch) String() string { return proto.CompactTextString(m) }
+func (*Rematch) ProtoMessage()    {}
+func (*Rematch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *Rematch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rematch.Unmarshal(m, b)
+}
+func (m *Rematch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rematch.Marshal(b, m, deterministic)
+}
+func (m *Rematch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rematch.Merge(m, src)
+}
+func (m *Rematch) XXX_Size() int {
+	return xxx_messageInfo_Rematch.Size(m)
+}
+func (m *Rematch) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rematch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rematch proto.InternalMessageInfo
+
+func (m *Rematch) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Rematch) GetTimeout() bool {
+	if m != nil {
+		return m.Timeout
+	}
+	return false
+}
+
+func (m *Rematch) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A player is out of the game in progress, in matches with more than two seats. Their marks stay on the board and
+// the remaining players carry on taking turns, until only one is left.
+type PlayerEliminated struct {
+	// The user ID of the player who was eliminated.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The mark the player held.
+	Mark Mark `protobuf:"varint,2,opt,name=mark,proto3,enum=api.Mark" json:"mark,omitempty"`
+	// Why the player was eliminated: they timed out, resigned or left.
+	Reason DoneReason `protobuf:"varint,3,opt,name=reason,proto3,enum=api.DoneReason" json:"reason,omitempty"`
+	// Whose turn it is to play now.
+	NextMark Mark `protobuf:"varint,4,opt,name=next_mark,json=nextMark,proto3,enum=api.Mark" json:"next_mark,omitempty"`
+	// The deadline time by which the player to move must submit their move, or forfeit.
+	Deadline int64 `protobuf:"varint,5,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one.
+	Sequence             int64    `protobuf:"varint,6,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlayerEliminated) Reset()         { *m = PlayerEliminated{} }
+func (m *PlayerEliminated) String() string { return proto.CompactTextString(m) }
+func (*PlayerEliminated) ProtoMessage()    {}
+func (*PlayerEliminated) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *PlayerEliminated) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerEliminated.Unmarshal(m, b)
+}
+func (m *PlayerEliminated) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerEliminated.Marshal(b, m, deterministic)
+}
+func (m *PlayerEliminated) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerEliminated.Merge(m, src)
+}
+func (m *PlayerEliminated) XXX_Size() int {
+	return xxx_messageInfo_PlayerEliminated.Size(m)
+}
+func (m *PlayerEliminated) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerEliminated.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerEliminated proto.InternalMessageInfo
+
+func (m *PlayerEliminated) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *PlayerEliminated) GetMark() Mark {
+	if m != nil {
+		return m.Mark
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *PlayerEliminated) GetReason() DoneReason {
+	if m != nil {
+		return m.Reason
+	}
+	return DoneReason_DONE_REASON_UNSPECIFIED
+}
+
+func (m *PlayerEliminated) GetNextMark() Mark {
+	if m != nil {
+		return m.NextMark
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *PlayerEliminated) GetDeadline() int64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *PlayerEliminated) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A player dropped out of the game in progress.
+type OpponentDisconnected struct {
+	// The user ID of the player who disconnected.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The deadline time by which the player must reconnect, or forfeit.
+	Deadline int64 `protobuf:"varint,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one.
+	Sequence             int64    `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OpponentDisconnected) Reset()         { *m = OpponentDisconnected{} }
+func (m *OpponentDisconnected) String() string { return proto.CompactTextString(m) }
+func (*OpponentDisconnected) ProtoMessage()    {}
+func (*OpponentDisconnected) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *OpponentDisconnected) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OpponentDisconnected.Unmarshal(m, b)
+}
+func (m *OpponentDisconnected) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OpponentDisconnected.Marshal(b, m, deterministic)
+}
+func (m *OpponentDisconnected) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OpponentDisconnected.Merge(m, src)
+}
+func (m *OpponentDisconnected) XXX_Size() int {
+	return xxx_messageInfo_OpponentDisconnected.Size(m)
+}
+func (m *OpponentDisconnected) XXX_DiscardUnknown() {
+	xxx_messageInfo_OpponentDisconnected.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OpponentDisconnected proto.InternalMessageInfo
+
+func (m *OpponentDisconnected) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *OpponentDisconnected) GetDeadline() int64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *OpponentDisconnected) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A disconnected player returned to the game in progress.
+type OpponentReconnected struct {
+	// The user ID of the player who reconnected.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The deadline time by which the player to move must submit their move, now that the turn timer has resumed.
+	Deadline int64 `protobuf:"varint,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// Increases with every state message the match sends, so clients can tell when they've missed one.
+	Sequence             int64    `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OpponentReconnected) Reset()         { *m = OpponentReconnected{} }
+func (m *OpponentReconnected) String() string { return proto.CompactTextString(m) }
+func (*OpponentReconnected) ProtoMessage()    {}
+func (*OpponentReconnected) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *OpponentReconnected) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OpponentReconnected.Unmarshal(m, b)
+}
+func (m *OpponentReconnected) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OpponentReconnected.Marshal(b, m, deterministic)
+}
+func (m *OpponentReconnected) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OpponentReconnected.Merge(m, src)
+}
+func (m *OpponentReconnected) XXX_Size() int {
+	return xxx_messageInfo_OpponentReconnected.Size(m)
+}
+func (m *OpponentReconnected) XXX_DiscardUnknown() {
+	xxx_messageInfo_OpponentReconnected.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OpponentReconnected proto.InternalMessageInfo
+
+func (m *OpponentReconnected) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *OpponentReconnected) GetDeadline() int64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *OpponentReconnected) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// A player intends to make a move.
+type Move struct {
+	// The position the player wants to place their mark in, counted row by row from the top left cell. In ultimate
+	// tic-tac-toe it's the sub-board times 9 plus the cell within it, both counted row by row, as laid out in
+	// UltimateBoard.
+	Position int32 `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	// The mark to place, in variants that let players choose. Unspecified places the mark the variant gives the player.
+	Mark                 Mark     `protobuf:"varint,2,opt,name=mark,proto3,enum=api.Mark" json:"mark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Move) Reset()         { *m = Move{} }
+func (m *Move) String() string { return proto.CompactTextString(m) }
+func (*Move) ProtoMessage()    {}
+func (*Move) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+
+func (m *Move) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Move.Unmarshal(m, b)
+}
+func (m *Move) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Move.Marshal(b, m, deterministic)
+}
+func (m *Move) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Move.Merge(m, src)
+}
+func (m *Move) XXX_Size() int {
+	return xxx_messageInfo_Move.Size(m)
+}
+func (m *Move) XXX_DiscardUnknown() {
+	xxx_messageInfo_Move.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Move proto.InternalMessageInfo
+
+func (m *Move) GetPosition() int32 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+func (m *Move) GetMark() Mark {
+	if m != nil {
+		return m.Mark
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+// Payload for an RPC request to find a match.
+type RpcFindMatchRequest struct {
+	// User can choose a fast or normal speed match.
+	Fast bool `protobuf:"varint,1,opt,name=fast,proto3" json:"fast,omitempty"`
+	// The number of cells along each side of the board. Defaults to 3, or 6 with more than two seats, which need at
+	// least 5.
+	BoardSize int32 `protobuf:"varint,2,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win. Defaults to the board size capped at 5, or 4 with more than two seats.
+	WinLength int32 `protobuf:"varint,3,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Play against a server-side bot if no other player joins in time. Unspecified waits for a player indefinitely.
+	BotDifficulty BotDifficulty `protobuf:"varint,4,opt,name=bot_difficulty,json=botDifficulty,proto3,enum=api.BotDifficulty" json:"bot_difficulty,omitempty"`
+	// Play a best-of series of this many games, such as 3, 5 or 7. Unset plays games indefinitely.
+	SeriesLength int32 `protobuf:"varint,5,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// Play with a chess clock: "bullet" (30s + 1s per move), "blitz" (60s + 2s) or "rapid" (180s + 5s). Unset limits
+	// each move to the fast or normal turn time instead.
+	TimeControl string `protobuf:"bytes,6,opt,name=time_control,json=timeControl,proto3" json:"time_control,omitempty"`
+	// Play ultimate tic-tac-toe, on a 3x3 grid of 3x3 boards. The board size and win length can't be chosen.
+	Ultimate bool `protobuf:"varint,7,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	// Play by different rules: "misere" (completing a line loses), "wild" (either player may place X or O, and
+	// completing a line of either wins) or "notakto" (both players place X, and completing a line loses). Unset plays by
+	// the standard rules.
+	Variant string `protobuf:"bytes,8,opt,name=variant,proto3" json:"variant,omitempty"`
+	// The number of players, from 2 to 4. Matches with more than two seats are played on a larger board, by the standard
+	// rules, and without a bot or series. Unset plays with two.
+	Seats                int32    `protobuf:"varint,9,opt,name=seats,proto3" json:"seats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcFindMatchRequest) Reset()         { *m = RpcFindMatchRequest{} }
+func (m *RpcFindMatchRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcFindMatchRequest) ProtoMessage()    {}
+func (*RpcFindMatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *RpcFindMatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcFindMatchRequest.Unmarshal(m, b)
+}
+func (m *RpcFindMatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcFindMatchRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcFindMatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcFindMatchRequest.Merge(m, src)
+}
+func (m *RpcFindMatchRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcFindMatchRequest.Size(m)
+}
+func (m *RpcFindMatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcFindMatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcFindMatchRequest proto.InternalMessageInfo
+
+func (m *RpcFindMatchRequest) GetFast() bool {
+	if m != nil {
+		return m.Fast
+	}
+	return false
+}
+
+func (m *RpcFindMatchRequest) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *RpcFindMatchRequest) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *RpcFindMatchRequest) GetBotDifficulty() BotDifficulty {
+	if m != nil {
+		return m.BotDifficulty
+	}
+	return BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED
+}
+
+func (m *RpcFindMatchRequest) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *RpcFindMatchRequest) GetTimeControl() string {
+	if m != nil {
+		return m.TimeControl
+	}
+	return ""
+}
+
+func (m *RpcFindMatchRequest) GetUltimate() bool {
+	if m != nil {
+		return m.Ultimate
+	}
+	return false
+}
+
+func (m *RpcFindMatchRequest) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *RpcFindMatchRequest) GetSeats() int32 {
+	if m != nil {
+		return m.Seats
+	}
+	return 0
+}
+
+// Payload for an RPC response containing match IDs the user can join.
+type RpcFindMatchResponse struct {
+	// The match that fits the user's request, with a seat held for them.
+	MatchIds []string `protobuf:"bytes,1,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
+	// Claims the held seat when passed as "reservation" in the join metadata. The seat is given up if the user doesn't
+	// join within 15 seconds.
+	Reservation          string   `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcFindMatchResponse) Reset()         { *m = RpcFindMatchResponse{} }
+func (m *RpcFindMatchResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcFindMatchResponse) ProtoMessage()    {}
+func (*RpcFindMatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+
+func (m *RpcFindMatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcFindMatchResponse.Unmarshal(m, b)
+}
+func (m *RpcFindMatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcFindMatchResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcFindMatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcFindMatchResponse.Merge(m, src)
+}
+func (m *RpcFindMatchResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcFindMatchResponse.Size(m)
+}
+func (m *RpcFindMatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcFindMatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcFindMatchResponse proto.InternalMessageInfo
+
+func (m *RpcFindMatchResponse) GetMatchIds() []string {
+	if m != nil {
+		return m.MatchIds
+	}
+	return nil
+}
+
+func (m *RpcFindMatchResponse) GetReservation() string {
+	if m != nil {
+		return m.Reservation
+	}
+	return ""
+}
+
+// A single accepted move in a recorded game.
+type ReplayMove struct {
+	// The user who made the move.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The mark placed by the move.
+	Mark Mark `protobuf:"varint,2,opt,name=mark,proto3,enum=api.Mark" json:"mark,omitempty"`
+	// The board position the mark was placed in.
+	Position int32 `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	// Match ticks elapsed since the start of the game when the move was made.
+	Tick                 int64    `protobuf:"varint,4,opt,name=tick,proto3" json:"tick,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayMove) Reset()         { *m = ReplayMove{} }
+func (m *ReplayMove) String() string { return proto.CompactTextString(m) }
+func (*ReplayMove) ProtoMessage()    {}
+func (*ReplayMove) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *ReplayMove) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayMove.Unmarshal(m, b)
+}
+func (m *ReplayMove) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayMove.Marshal(b, m, deterministic)
+}
+func (m *ReplayMove) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayMove.Merge(m, src)
+}
+func (m *ReplayMove) XXX_Size() int {
+	return xxx_messageInfo_ReplayMove.Size(m)
+}
+func (m *ReplayMove) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayMove.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayMove proto.InternalMessageInfo
+
+func (m *ReplayMove) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ReplayMove) GetMark() Mark {
+	if m != nil {
+		return m.Mark
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *ReplayMove) GetPosition() int32 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+func (m *ReplayMove) GetTick() int64 {
+	if m != nil {
+		return m.Tick
+	}
+	return 0
+}
+
+// The complete record of a finished game round.
+type Replay struct {
+	// Unique identifier of this replay.
+	ReplayId string `protobuf:"bytes,1,opt,name=replay_id,json=replayId,proto3" json:"replay_id,omitempty"`
+	// The match the game was played in.
+	MatchId string `protobuf:"bytes,2,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The number of cells along each side of the square board.
+	BoardSize int32 `protobuf:"varint,3,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win.
+	WinLength int32 `protobuf:"varint,4,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// The assignments of the marks to players for this round.
+	Marks map[string]Mark `protobuf:"bytes,5,rep,name=marks,proto3" json:"marks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=api.Mark"`
+	// Every accepted move, in the order they were played.
+	Moves []*ReplayMove `protobuf:"bytes,6,rep,name=moves,proto3" json:"moves,omitempty"`
+	// The winner of the game, if any. Unspecified if it's a draw.
+	Winner Mark `protobuf:"varint,7,opt,name=winner,proto3,enum=api.Mark" json:"winner,omitempty"`
+	// Winner board positions, if any.
+	WinnerPositions []int32 `protobuf:"varint,8,rep,packed,name=winner_positions,json=winnerPositions,proto3" json:"winner_positions,omitempty"`
+	// The match tick rate, to convert move ticks into time.
+	TickRate int32 `protobuf:"varint,9,opt,name=tick_rate,json=tickRate,proto3" json:"tick_rate,omitempty"`
+	// Game start time.
+	StartTime int64 `protobuf:"varint,10,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Game end time.
+	EndTime int64 `protobuf:"varint,11,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Why the game ended.
+	Reason DoneReason `protobuf:"varint,12,opt,name=reason,proto3,enum=api.DoneReason" json:"reason,omitempty"`
+	// Why the player with the first move was chosen.
+	FirstMoveReason FirstMoveReason `protobuf:"varint,13,opt,name=first_move_reason,json=firstMoveReason,proto3,enum=api.FirstMoveReason" json:"first_move_reason,omitempty"`
+	// The seed of the match random number generator, used for random draws such as the first move.
+	Seed int64 `protobuf:"varint,14,opt,name=seed,proto3" json:"seed,omitempty"`
+	// Whether the game was ultimate tic-tac-toe, with positions numbered by sub-board.
+	Ultimate bool `protobuf:"varint,15,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	// The rules the game was played by.
+	Variant              string   `protobuf:"bytes,16,opt,name=variant,proto3" json:"variant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Replay) Reset()         { *m = Replay{} }
+func (m *Replay) String() string { return proto.CompactTextString(m) }
+func (*Replay) ProtoMessage()    {}
+func (*Replay) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+
+func (m *Replay) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Replay.Unmarshal(m, b)
+}
+func (m *Replay) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Replay.Marshal(b, m, deterministic)
+}
+func (m *Replay) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Replay.Merge(m, src)
+}
+func (m *Replay) XXX_Size() int {
+	return xxx_messageInfo_Replay.Size(m)
+}
+func (m *Replay) XXX_DiscardUnknown() {
+	xxx_messageInfo_Replay.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Replay proto.InternalMessageInfo
+
+func (m *Replay) GetReplayId() string {
+	if m != nil {
+		return m.ReplayId
+	}
+	return ""
+}
+
+func (m *Replay) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *Replay) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *Replay) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *Replay) GetMarks() map[string]Mark {
+	if m != nil {
+		return m.Marks
+	}
+	return nil
+}
+
+func (m *Replay) GetMoves() []*ReplayMove {
+	if m != nil {
+		return m.Moves
+	}
+	return nil
+}
+
+func (m *Replay) GetWinner() Mark {
+	if m != nil {
+		return m.Winner
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *Replay) GetWinnerPositions() []int32 {
+	if m != nil {
+		return m.WinnerPositions
+	}
+	return nil
+}
+
+func (m *Replay) GetTickRate() int32 {
+	if m != nil {
+		return m.TickRate
+	}
+	return 0
+}
+
+func (m *Replay) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *Replay) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *Replay) GetReason() DoneReason {
+	if m != nil {
+		return m.Reason
+	}
+	return DoneReason_DONE_REASON_UNSPECIFIED
+}
+
+func (m *Replay) GetFirstMoveReason() FirstMoveReason {
+	if m != nil {
+		return m.FirstMoveReason
+	}
+	return FirstMoveReason_FIRST_MOVE_REASON_UNSPECIFIED
+}
+
+func (m *Replay) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *Replay) GetUltimate() bool {
+	if m != nil {
+		return m.Ultimate
+	}
+	return false
+}
+
+func (m *Replay) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+// A short description of a recorded game in a player's history.
+type ReplaySummary struct {
+	// Unique identifier of the replay, to fetch in full.
+	ReplayId string `protobuf:"bytes,1,opt,name=replay_id,json=replayId,proto3" json:"replay_id,omitempty"`
+	// The assignments of the marks to players for this round.
+	Marks map[string]Mark `protobuf:"bytes,2,rep,name=marks,proto3" json:"marks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=api.Mark"`
+	// The winner of the game, if any. Unspecified if it's a draw.
+	Winner Mark `protobuf:"varint,3,opt,name=winner,proto3,enum=api.Mark" json:"winner,omitempty"`
+	// Game end time.
+	EndTime              int64    `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplaySummary) Reset()         { *m = ReplaySummary{} }
+func (m *ReplaySummary) String() string { return proto.CompactTextString(m) }
+func (*ReplaySummary) ProtoMessage()    {}
+func (*ReplaySummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
+
+func (m *ReplaySummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaySummary.Unmarshal(m, b)
+}
+func (m *ReplaySummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaySummary.Marshal(b, m, deterministic)
+}
+func (m *ReplaySummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaySummary.Merge(m, src)
+}
+func (m *ReplaySummary) XXX_Size() int {
+	return xxx_messageInfo_ReplaySummary.Size(m)
+}
+func (m *ReplaySummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaySummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaySummary proto.InternalMessageInfo
+
+func (m *ReplaySummary) GetReplayId() string {
+	if m != nil {
+		return m.ReplayId
+	}
+	return ""
+}
+
+func (m *ReplaySummary) GetMarks() map[string]Mark {
+	if m != nil {
+		return m.Marks
+	}
+	return nil
+}
+
+func (m *ReplaySummary) GetWinner() Mark {
+	if m != nil {
+		return m.Winner
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *ReplaySummary) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+// Payload for an RPC request to get a replay.
+type RpcGetReplayRequest struct {
+	// The replay to fetch.
+	ReplayId             string   `protobuf:"bytes,1,opt,name=replay_id,json=replayId,proto3" json:"replay_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcGetReplayRequest) Reset()         { *m = RpcGetReplayRequest{} }
+func (m *RpcGetReplayRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcGetReplayRequest) ProtoMessage()    {}
+func (*RpcGetReplayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
+}
+
+func (m *RpcGetReplayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcGetReplayRequest.Unmarshal(m, b)
+}
+func (m *RpcGetReplayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcGetReplayRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcGetReplayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcGetReplayRequest.Merge(m, src)
+}
+func (m *RpcGetReplayRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcGetReplayRequest.Size(m)
+}
+func (m *RpcGetReplayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcGetReplayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcGetReplayRequest proto.InternalMessageInfo
+
+func (m *RpcGetReplayRequest) GetReplayId() string {
+	if m != nil {
+		return m.ReplayId
+	}
+	return ""
+}
+
+// Payload for an RPC request to list the caller's recent games.
+type RpcListReplaysRequest struct {
+	// Maximum number of replays to return. Defaults to 10.
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Cursor from a previous response to fetch the next page.
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcListReplaysRequest) Reset()         { *m = RpcListReplaysRequest{} }
+func (m *RpcListReplaysRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcListReplaysRequest) ProtoMessage()    {}
+func (*RpcListReplaysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+}
+
+func (m *RpcListReplaysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcListReplaysRequest.Unmarshal(m, b)
+}
+func (m *RpcListReplaysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcListReplaysRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcListReplaysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcListReplaysRequest.Merge(m, src)
+}
+func (m *RpcListReplaysRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcListReplaysRequest.Size(m)
+}
+func (m *RpcListReplaysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcListReplaysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcListReplaysRequest proto.InternalMessageInfo
+
+func (m *RpcListReplaysRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *RpcListReplaysRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// Payload for an RPC response containing the caller's recent games, newest first.
+type RpcListReplaysResponse struct {
+	// The recent games.
+	Replays []*ReplaySummary `protobuf:"bytes,1,rep,name=replays,proto3" json:"replays,omitempty"`
+	// Cursor to fetch the next page, if there is one.
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcListReplaysResponse) Reset()         { *m = RpcListReplaysResponse{} }
+func (m *RpcListReplaysResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcListReplaysResponse) ProtoMessage()    {}
+func (*RpcListReplaysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+}
+
+func (m *RpcListReplaysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcListReplaysResponse.Unmarshal(m, b)
+}
+func (m *RpcListReplaysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcListReplaysResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcListReplaysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcListReplaysResponse.Merge(m, src)
+}
+func (m *RpcListReplaysResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcListReplaysResponse.Size(m)
+}
+func (m *RpcListReplaysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcListReplaysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcListReplaysResponse proto.InternalMessageInfo
+
+func (m *RpcListReplaysResponse) GetReplays() []*ReplaySummary {
+	if m != nil {
+		return m.Replays
+	}
+	return nil
+}
+
+func (m *RpcListReplaysResponse) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// Payload for an RPC request to get a match.
 type RpcGetMatchRequest struct {
 	// User can choose a fast or normal speed match.
-	Fast                 bool     `protobuf:"varint,1,opt,name=fast,proto3" json:"fast,omitempty"`
+	Fast                 bool     `protobuf:"varint,1,opt,name=fast,proto3" json:"fast,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcGetMatchRequest) Reset()         { *m = RpcGetMatchRequest{} }
+func (m *RpcGetMatchRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcGetMatchRequest) ProtoMessage()    {}
+func (*RpcGetMatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *RpcGetMatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcGetMatchRequest.Unmarshal(m, b)
+}
+func (m *RpcGetMatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcGetMatchRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcGetMatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcGetMatchRequest.Merge(m, src)
+}
+func (m *RpcGetMatchRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcGetMatchRequest.Size(m)
+}
+func (m *RpcGetMatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcGetMatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcGetMatchRequest proto.InternalMessageInfo
+
+func (m *RpcGetMatchRequest) GetFast() bool {
+	if m != nil {
+		return m.Fast
+	}
+	return false
+}
+
+// Payload for an RPC response containing match IDs the user can join.
+type RpcGetMatchResponse struct {
+	// One or more matches that fit the user's request.
+	MatchIds             []string `protobuf:"bytes,1,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcGetMatchResponse) Reset()         { *m = RpcGetMatchResponse{} }
+func (m *RpcGetMatchResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcGetMatchResponse) ProtoMessage()    {}
+func (*RpcGetMatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+
+func (m *RpcGetMatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcGetMatchResponse.Unmarshal(m, b)
+}
+func (m *RpcGetMatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcGetMatchResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcGetMatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcGetMatchResponse.Merge(m, src)
+}
+func (m *RpcGetMatchResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcGetMatchResponse.Size(m)
+}
+func (m *RpcGetMatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcGetMatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcGetMatchResponse proto.InternalMessageInfo
+
+func (m *RpcGetMatchResponse) GetMatchIds() []string {
+	if m != nil {
+		return m.MatchIds
+	}
+	return nil
+}
+
+// A private match's invite code, as kept in storage under the code.
+type PrivateMatchCode struct {
+	// The match the code invites players to. Empty while the match is being created.
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The time after which the code can no longer be used to join.
+	ExpireTime           int64    `protobuf:"varint,2,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrivateMatchCode) Reset()         { *m = PrivateMatchCode{} }
+func (m *PrivateMatchCode) String() string { return proto.CompactTextString(m) }
+func (*PrivateMatchCode) ProtoMessage()    {}
+func (*PrivateMatchCode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+
+func (m *PrivateMatchCode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateMatchCode.Unmarshal(m, b)
+}
+func (m *PrivateMatchCode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateMatchCode.Marshal(b, m, deterministic)
+}
+func (m *PrivateMatchCode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateMatchCode.Merge(m, src)
+}
+func (m *PrivateMatchCode) XXX_Size() int {
+	return xxx_messageInfo_PrivateMatchCode.Size(m)
+}
+func (m *PrivateMatchCode) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateMatchCode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateMatchCode proto.InternalMessageInfo
+
+func (m *PrivateMatchCode) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *PrivateMatchCode) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+// Payload for an RPC request to create a private match, only joinable with its invite code.
+type RpcCreatePrivateMatchRequest struct {
+	// User can choose a fast or normal speed match.
+	Fast bool `protobuf:"varint,1,opt,name=fast,proto3" json:"fast,omitempty"`
+	// The number of cells along each side of the board. Defaults to 3.
+	BoardSize int32 `protobuf:"varint,2,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win. Defaults to the board size, capped at 5.
+	WinLength int32 `protobuf:"varint,3,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Play a best-of series of this many games, such as 3, 5 or 7. Unset plays games indefinitely.
+	SeriesLength int32 `protobuf:"varint,4,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// Play with a chess clock, as in RpcFindMatchRequest. Unset limits each move to the fast or normal turn time.
+	TimeControl          string   `protobuf:"bytes,5,opt,name=time_control,json=timeControl,proto3" json:"time_control,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcCreatePrivateMatchRequest) Reset()         { *m = RpcCreatePrivateMatchRequest{} }
+func (m *RpcCreatePrivateMatchRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcCreatePrivateMatchRequest) ProtoMessage()    {}
+func (*RpcCreatePrivateMatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+
+func (m *RpcCreatePrivateMatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcCreatePrivateMatchRequest.Unmarshal(m, b)
+}
+func (m *RpcCreatePrivateMatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcCreatePrivateMatchRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcCreatePrivateMatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcCreatePrivateMatchRequest.Merge(m, src)
+}
+func (m *RpcCreatePrivateMatchRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcCreatePrivateMatchRequest.Size(m)
+}
+func (m *RpcCreatePrivateMatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcCreatePrivateMatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcCreatePrivateMatchRequest proto.InternalMessageInfo
+
+func (m *RpcCreatePrivateMatchRequest) GetFast() bool {
+	if m != nil {
+		return m.Fast
+	}
+	return false
+}
+
+func (m *RpcCreatePrivateMatchRequest) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *RpcCreatePrivateMatchRequest) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *RpcCreatePrivateMatchRequest) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *RpcCreatePrivateMatchRequest) GetTimeControl() string {
+	if m != nil {
+		return m.TimeControl
+	}
+	return ""
+}
+
+// Payload for an RPC response containing the new private match and the code to share with the opponent.
+type RpcCreatePrivateMatchResponse struct {
+	// The private match, for the creator to join.
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The short invite code other players can join with.
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// The time after which the code can no longer be used to join.
+	ExpireTime           int64    `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcCreatePrivateMatchResponse) Reset()         { *m = RpcCreatePrivateMatchResponse{} }
+func (m *RpcCreatePrivateMatchResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcCreatePrivateMatchResponse) ProtoMessage()    {}
+func (*RpcCreatePrivateMatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+
+func (m *RpcCreatePrivateMatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcCreatePrivateMatchResponse.Unmarshal(m, b)
+}
+func (m *RpcCreatePrivateMatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcCreatePrivateMatchResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcCreatePrivateMatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcCreatePrivateMatchResponse.Merge(m, src)
+}
+func (m *RpcCreatePrivateMatchResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcCreatePrivateMatchResponse.Size(m)
+}
+func (m *RpcCreatePrivateMatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcCreatePrivateMatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcCreatePrivateMatchResponse proto.InternalMessageInfo
+
+func (m *RpcCreatePrivateMatchResponse) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *RpcCreatePrivateMatchResponse) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *RpcCreatePrivateMatchResponse) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+// Payload for an RPC request to look up a private match by its invite code.
+type RpcJoinByCodeRequest struct {
+	// The invite code, not case sensitive.
+	Code                 string   `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcJoinByCodeRequest) Reset()         { *m = RpcJoinByCodeRequest{} }
+func (m *RpcJoinByCodeRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcJoinByCodeRequest) ProtoMessage()    {}
+func (*RpcJoinByCodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+}
+
+func (m *RpcJoinByCodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcJoinByCodeRequest.Unmarshal(m, b)
+}
+func (m *RpcJoinByCodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcJoinByCodeRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcJoinByCodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcJoinByCodeRequest.Merge(m, src)
+}
+func (m *RpcJoinByCodeRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcJoinByCodeRequest.Size(m)
+}
+func (m *RpcJoinByCodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcJoinByCodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcJoinByCodeRequest proto.InternalMessageInfo
+
+func (m *RpcJoinByCodeRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+// Payload for an RPC response containing the private match the invite code belongs to.
+type RpcJoinByCodeResponse struct {
+	// The private match to join.
+	MatchId              string   `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcJoinByCodeResponse) Reset()         { *m = RpcJoinByCodeResponse{} }
+func (m *RpcJoinByCodeResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcJoinByCodeResponse) ProtoMessage()    {}
+func (*RpcJoinByCodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{29}
+}
+
+func (m *RpcJoinByCodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcJoinByCodeResponse.Unmarshal(m, b)
+}
+func (m *RpcJoinByCodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcJoinByCodeResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcJoinByCodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcJoinByCodeResponse.Merge(m, src)
+}
+func (m *RpcJoinByCodeResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcJoinByCodeResponse.Size(m)
+}
+func (m *RpcJoinByCodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcJoinByCodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcJoinByCodeResponse proto.InternalMessageInfo
+
+func (m *RpcJoinByCodeResponse) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+// A challenge to a friend waiting for an answer, as kept in storage by the challenged user.
+type Challenge struct {
+	// The match reserved for the challenger and the challenged user.
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The user ID of the player who sent the challenge.
+	ChallengerId string `protobuf:"bytes,2,opt,name=challenger_id,json=challengerId,proto3" json:"challenger_id,omitempty"`
+	// The time after which the challenge can no longer be accepted.
+	ExpireTime           int64    `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Challenge) Reset()         { *m = Challenge{} }
+func (m *Challenge) String() string { return proto.CompactTextString(m) }
+func (*Challenge) ProtoMessage()    {}
+func (*Challenge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{30}
+}
+
+func (m *Challenge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Challenge.Unmarshal(m, b)
+}
+func (m *Challenge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Challenge.Marshal(b, m, deterministic)
+}
+func (m *Challenge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Challenge.Merge(m, src)
+}
+func (m *Challenge) XXX_Size() int {
+	return xxx_messageInfo_Challenge.Size(m)
+}
+func (m *Challenge) XXX_DiscardUnknown() {
+	xxx_messageInfo_Challenge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Challenge proto.InternalMessageInfo
+
+func (m *Challenge) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *Challenge) GetChallengerId() string {
+	if m != nil {
+		return m.ChallengerId
+	}
+	return ""
+}
+
+func (m *Challenge) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+// Payload for an RPC request to challenge a friend to a match.
+type RpcChallengeFriendRequest struct {
+	// The user ID of the friend to challenge.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// User can choose a fast or normal speed match.
+	Fast bool `protobuf:"varint,2,opt,name=fast,proto3" json:"fast,omitempty"`
+	// The number of cells along each side of the board. Defaults to 3.
+	BoardSize int32 `protobuf:"varint,3,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	// The number of marks in a row needed to win. Defaults to the board size, capped at 5.
+	WinLength int32 `protobuf:"varint,4,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	// Play a best-of series of this many games, such as 3, 5 or 7. Unset plays games indefinitely.
+	SeriesLength int32 `protobuf:"varint,5,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	// Play with a chess clock, as in RpcFindMatchRequest. Unset limits each move to the fast or normal turn time.
+	TimeControl          string   `protobuf:"bytes,6,opt,name=time_control,json=timeControl,proto3" json:"time_control,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcChallengeFriendRequest) Reset()         { *m = RpcChallengeFriendRequest{} }
+func (m *RpcChallengeFriendRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcChallengeFriendRequest) ProtoMessage()    {}
+func (*RpcChallengeFriendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{31}
+}
+
+func (m *RpcChallengeFriendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcChallengeFriendRequest.Unmarshal(m, b)
+}
+func (m *RpcChallengeFriendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcChallengeFriendRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcChallengeFriendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcChallengeFriendRequest.Merge(m, src)
+}
+func (m *RpcChallengeFriendRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcChallengeFriendRequest.Size(m)
+}
+func (m *RpcChallengeFriendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcChallengeFriendRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcChallengeFriendRequest proto.InternalMessageInfo
+
+func (m *RpcChallengeFriendRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *RpcChallengeFriendRequest) GetFast() bool {
+	if m != nil {
+		return m.Fast
+	}
+	return false
+}
+
+func (m *RpcChallengeFriendRequest) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *RpcChallengeFriendRequest) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *RpcChallengeFriendRequest) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *RpcChallengeFriendRequest) GetTimeControl() string {
+	if m != nil {
+		return m.TimeControl
+	}
+	return ""
+}
+
+// Payload for an RPC response containing the match reserved for a challenge.
+type RpcChallengeFriendResponse struct {
+	// The reserved match, for the challenger to join.
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The time after which the challenge can no longer be accepted.
+	ExpireTime           int64    `protobuf:"varint,2,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcChallengeFriendResponse) Reset()         { *m = RpcChallengeFriendResponse{} }
+func (m *RpcChallengeFriendResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcChallengeFriendResponse) ProtoMessage()    {}
+func (*RpcChallengeFriendResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{32}
+}
+
+func (m *RpcChallengeFriendResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcChallengeFriendResponse.Unmarshal(m, b)
+}
+func (m *RpcChallengeFriendResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcChallengeFriendResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcChallengeFriendResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcChallengeFriendResponse.Merge(m, src)
+}
+func (m *RpcChallengeFriendResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcChallengeFriendResponse.Size(m)
+}
+func (m *RpcChallengeFriendResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcChallengeFriendResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcChallengeFriendResponse proto.InternalMessageInfo
+
+func (m *RpcChallengeFriendResponse) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *RpcChallengeFriendResponse) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+// Payload for an RPC request to accept or decline a challenge.
+type RpcChallengeRequest struct {
+	// The match reserved for the challenge, as given in the challenge notification.
+	MatchId              string   `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcChallengeRequest) Reset()         { *m = RpcChallengeRequest{} }
+func (m *RpcChallengeRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcChallengeRequest) ProtoMessage()    {}
+func (*RpcChallengeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{33}
+}
+
+func (m *RpcChallengeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcChallengeRequest.Unmarshal(m, b)
+}
+func (m *RpcChallengeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcChallengeRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcChallengeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcChallengeRequest.Merge(m, src)
+}
+func (m *RpcChallengeRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcChallengeRequest.Size(m)
+}
+func (m *RpcChallengeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcChallengeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcChallengeRequest proto.InternalMessageInfo
+
+func (m *RpcChallengeRequest) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+// Payload for an RPC response to accepting a challenge.
+type RpcAcceptChallengeResponse struct {
+	// The reserved match to join.
+	MatchId              string   `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcAcceptChallengeResponse) Reset()         { *m = RpcAcceptChallengeResponse{} }
+func (m *RpcAcceptChallengeResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcAcceptChallengeResponse) ProtoMessage()    {}
+func (*RpcAcceptChallengeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{34}
+}
+
+func (m *RpcAcceptChallengeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcAcceptChallengeResponse.Unmarshal(m, b)
+}
+func (m *RpcAcceptChallengeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcAcceptChallengeResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcAcceptChallengeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcAcceptChallengeResponse.Merge(m, src)
+}
+func (m *RpcAcceptChallengeResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcAcceptChallengeResponse.Size(m)
+}
+func (m *RpcAcceptChallengeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcAcceptChallengeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcAcceptChallengeResponse proto.InternalMessageInfo
+
+func (m *RpcAcceptChallengeResponse) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+// The state of a match saved as the server shuts down, so the match can be recreated once the server is back.
+type SavedMatch struct {
+	// The match the state was saved from.
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The settings the match was created with.
+	Fast            bool            `protobuf:"varint,2,opt,name=fast,proto3" json:"fast,omitempty"`
+	BoardSize       int32           `protobuf:"varint,3,opt,name=board_size,json=boardSize,proto3" json:"board_size,omitempty"`
+	WinLength       int32           `protobuf:"varint,4,opt,name=win_length,json=winLength,proto3" json:"win_length,omitempty"`
+	BotDifficulty   BotDifficulty   `protobuf:"varint,5,opt,name=bot_difficulty,json=botDifficulty,proto3,enum=api.BotDifficulty" json:"bot_difficulty,omitempty"`
+	SeriesLength    int32           `protobuf:"varint,6,opt,name=series_length,json=seriesLength,proto3" json:"series_length,omitempty"`
+	TimeControl     string          `protobuf:"bytes,7,opt,name=time_control,json=timeControl,proto3" json:"time_control,omitempty"`
+	FirstMovePolicy FirstMovePolicy `protobuf:"varint,8,opt,name=first_move_policy,json=firstMovePolicy,proto3,enum=api.FirstMovePolicy" json:"first_move_policy,omitempty"`
+	// Whether a game was in progress.
+	Playing bool `protobuf:"varint,9,opt,name=playing,proto3" json:"playing,omitempty"`
+	// The current state of the board.
+	Board []Mark `protobuf:"varint,10,rep,packed,name=board,proto3,enum=api.Mark" json:"board,omitempty"`
+	// The assignments of the marks to players for this round.
+	Marks map[string]Mark `protobuf:"bytes,11,rep,name=marks,proto3" json:"marks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=api.Mark"`
+	// Whose turn it is to play.
+	Mark Mark `protobuf:"varint,12,opt,name=mark,proto3,enum=api.Mark" json:"mark,omitempty"`
+	// Ticks left for the player to move.
+	DeadlineRemainingTicks int64 `protobuf:"varint,13,opt,name=deadline_remaining_ticks,json=deadlineRemainingTicks,proto3" json:"deadline_remaining_ticks,omitempty"`
+	// Ticks left in each player's time bank, if the match is played with a chess clock.
+	Clocks map[string]int64 `protobuf:"bytes,14,rep,name=clocks,proto3" json:"clocks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Games won so far in the series by each player's user ID, if this is a series.
+	SeriesScore map[string]int32 `protobuf:"bytes,15,rep,name=series_score,json=seriesScore,proto3" json:"series_score,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of games started in the match so far.
+	GamesPlayed int32 `protobuf:"varint,16,opt,name=games_played,json=gamesPlayed,proto3" json:"games_played,omitempty"`
+	// The winner of the most recent game, if one has finished.
+	Winner Mark `protobuf:"varint,17,opt,name=winner,proto3,enum=api.Mark" json:"winner,omitempty"`
+	// The record of the game in progress.
+	Replay *Replay `protobuf:"bytes,18,opt,name=replay,proto3" json:"replay,omitempty"`
+	// Ticks elapsed since the game in progress started.
+	GameTicks int64 `protobuf:"varint,19,opt,name=game_ticks,json=gameTicks,proto3" json:"game_ticks,omitempty"`
+	// Whether the match plays ultimate tic-tac-toe.
+	Ultimate bool `protobuf:"varint,20,opt,name=ultimate,proto3" json:"ultimate,omitempty"`
+	// The sub-board the next move must be played in, in ultimate tic-tac-toe.
+	ActiveSubBoard int32 `protobuf:"varint,21,opt,name=active_sub_board,json=activeSubBoard,proto3" json:"active_sub_board,omitempty"`
+	// The rules the match is played by.
+	Variant string `protobuf:"bytes,22,opt,name=variant,proto3" json:"variant,omitempty"`
+	// The number of players the match is played with.
+	Seats int32 `protobuf:"varint,23,opt,name=seats,proto3" json:"seats,omitempty"`
+	// The user IDs of players eliminated from the game in progress.
+	Eliminated []string `protobuf:"bytes,24,rep,name=eliminated,proto3" json:"eliminated,omitempty"`
+	// Whether games in the match count towards player ratings.
+	Rated bool `protobuf:"varint,25,opt,name=rated,proto3" json:"rated,omitempty"`
+	// How long players may be away from a game in progress before they forfeit, in seconds.
+	ReconnectWindowSec int32 `protobuf:"varint,26,opt,name=reconnect_window_sec,json=reconnectWindowSec,proto3" json:"reconnect_window_sec,omitempty"`
+	// The number of spectators allowed to watch at once.
+	MaxSpectators int32 `protobuf:"varint,27,opt,name=max_spectators,json=maxSpectators,proto3" json:"max_spectators,omitempty"`
+	// How long a lone player waits before the bot takes the other seat, in seconds.
+	BotWaitSec int32 `protobuf:"varint,28,opt,name=bot_wait_sec,json=botWaitSec,proto3" json:"bot_wait_sec,omitempty"`
+	// The rating advertised in the match label.
+	Rating int32 `protobuf:"varint,29,opt,name=rating,proto3" json:"rating,omitempty"`
+	// The invite code of a private match.
+	PrivateCode          string   `protobuf:"bytes,30,opt,name=private_code,json=privateCode,proto3" json:"private_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SavedMatch) Reset()         { *m = SavedMatch{} }
+func (m *SavedMatch) String() string { return proto.CompactTextString(m) }
+func (*SavedMatch) ProtoMessage()    {}
+func (*SavedMatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{35}
+}
+
+func (m *SavedMatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SavedMatch.Unmarshal(m, b)
+}
+func (m *SavedMatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SavedMatch.Marshal(b, m, deterministic)
+}
+func (m *SavedMatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SavedMatch.Merge(m, src)
+}
+func (m *SavedMatch) XXX_Size() int {
+	return xxx_messageInfo_SavedMatch.Size(m)
+}
+func (m *SavedMatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_SavedMatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SavedMatch proto.InternalMessageInfo
+
+func (m *SavedMatch) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *SavedMatch) GetFast() bool {
+	if m != nil {
+		return m.Fast
+	}
+	return false
+}
+
+func (m *SavedMatch) GetBoardSize() int32 {
+	if m != nil {
+		return m.BoardSize
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetWinLength() int32 {
+	if m != nil {
+		return m.WinLength
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetBotDifficulty() BotDifficulty {
+	if m != nil {
+		return m.BotDifficulty
+	}
+	return BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED
+}
+
+func (m *SavedMatch) GetSeriesLength() int32 {
+	if m != nil {
+		return m.SeriesLength
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetTimeControl() string {
+	if m != nil {
+		return m.TimeControl
+	}
+	return ""
+}
+
+func (m *SavedMatch) GetFirstMovePolicy() FirstMovePolicy {
+	if m != nil {
+		return m.FirstMovePolicy
+	}
+	return FirstMovePolicy_FIRST_MOVE_POLICY_UNSPECIFIED
+}
+
+func (m *SavedMatch) GetPlaying() bool {
+	if m != nil {
+		return m.Playing
+	}
+	return false
+}
+
+func (m *SavedMatch) GetBoard() []Mark {
+	if m != nil {
+		return m.Board
+	}
+	return nil
+}
+
+func (m *SavedMatch) GetMarks() map[string]Mark {
+	if m != nil {
+		return m.Marks
+	}
+	return nil
+}
+
+func (m *SavedMatch) GetMark() Mark {
+	if m != nil {
+		return m.Mark
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *SavedMatch) GetDeadlineRemainingTicks() int64 {
+	if m != nil {
+		return m.DeadlineRemainingTicks
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetClocks() map[string]int64 {
+	if m != nil {
+		return m.Clocks
+	}
+	return nil
+}
+
+func (m *SavedMatch) GetSeriesScore() map[string]int32 {
+	if m != nil {
+		return m.SeriesScore
+	}
+	return nil
+}
+
+func (m *SavedMatch) GetGamesPlayed() int32 {
+	if m != nil {
+		return m.GamesPlayed
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetWinner() Mark {
+	if m != nil {
+		return m.Winner
+	}
+	return Mark_MARK_UNSPECIFIED
+}
+
+func (m *SavedMatch) GetReplay() *Replay {
+	if m != nil {
+		return m.Replay
+	}
+	return nil
+}
+
+func (m *SavedMatch) GetGameTicks() int64 {
+	if m != nil {
+		return m.GameTicks
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetUltimate() bool {
+	if m != nil {
+		return m.Ultimate
+	}
+	return false
+}
+
+func (m *SavedMatch) GetActiveSubBoard() int32 {
+	if m != nil {
+		return m.ActiveSubBoard
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *SavedMatch) GetSeats() int32 {
+	if m != nil {
+		return m.Seats
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetEliminated() []string {
+	if m != nil {
+		return m.Eliminated
+	}
+	return nil
+}
+
+func (m *SavedMatch) GetRated() bool {
+	if m != nil {
+		return m.Rated
+	}
+	return false
+}
+
+func (m *SavedMatch) GetReconnectWindowSec() int32 {
+	if m != nil {
+		return m.ReconnectWindowSec
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetMaxSpectators() int32 {
+	if m != nil {
+		return m.MaxSpectators
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetBotWaitSec() int32 {
+	if m != nil {
+		return m.BotWaitSec
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetRating() int32 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *SavedMatch) GetPrivateCode() string {
+	if m != nil {
+		return m.PrivateCode
+	}
+	return ""
+}
+
+// A warning to a client sending messages faster than the server accepts them.
+type InputWarning struct {
+	// The most messages accepted from a client each tick.
+	MaxMessagesPerTick int32 `protobuf:"varint,1,opt,name=max_messages_per_tick,json=maxMessagesPerTick,proto3" json:"max_messages_per_tick,omitempty"`
+	// The number of further ticks over the limit before the client is removed from the match.
+	StrikesLeft          int32    `protobuf:"varint,2,opt,name=strikes_left,json=strikesLeft,proto3" json:"strikes_left,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InputWarning) Reset()         { *m = InputWarning{} }
+func (m *InputWarning) String() string { return proto.CompactTextString(m) }
+func (*InputWarning) ProtoMessage()    {}
+func (*InputWarning) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{36}
+}
+
+func (m *InputWarning) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InputWarning.Unmarshal(m, b)
+}
+func (m *InputWarning) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InputWarning.Marshal(b, m, deterministic)
+}
+func (m *InputWarning) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InputWarning.Merge(m, src)
+}
+func (m *InputWarning) XXX_Size() int {
+	return xxx_messageInfo_InputWarning.Size(m)
+}
+func (m *InputWarning) XXX_DiscardUnknown() {
+	xxx_messageInfo_InputWarning.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InputWarning proto.InternalMessageInfo
+
+func (m *InputWarning) GetMaxMessagesPerTick() int32 {
+	if m != nil {
+		return m.MaxMessagesPerTick
+	}
+	return 0
+}
+
+func (m *InputWarning) GetStrikesLeft() int32 {
+	if m != nil {
+		return m.StrikesLeft
+	}
+	return 0
+}
+
+// Statistics for the whole match, as it closes.
+type MatchSummary struct {
+	// The number of games started in the match.
+	GamesPlayed int32 `protobuf:"varint,1,opt,name=games_played,json=gamesPlayed,proto3" json:"games_played,omitempty"`
+	// Games won in the series by each player's user ID, if this was a series.
+	SeriesScore map[string]int32 `protobuf:"bytes,2,rep,name=series_score,json=seriesScore,proto3" json:"series_score,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Messages answered with OPCODE_REJECTED, by user ID.
+	RejectedMessages map[string]int64 `protobuf:"bytes,3,rep,name=rejected_messages,json=rejectedMessages,proto3" json:"rejected_messages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Messages dropped for going over the per-tick limit, by user ID.
+	DroppedMessages      map[string]int64 `protobuf:"bytes,4,rep,name=dropped_messages,json=droppedMessages,proto3" json:"dropped_messages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *MatchSummary) Reset()         { *m = MatchSummary{} }
+func (m *MatchSummary) String() string { return proto.CompactTextString(m) }
+func (*MatchSummary) ProtoMessage()    {}
+func (*MatchSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{37}
+}
+
+func (m *MatchSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatchSummary.Unmarshal(m, b)
+}
+func (m *MatchSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatchSummary.Marshal(b, m, deterministic)
+}
+func (m *MatchSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatchSummary.Merge(m, src)
+}
+func (m *MatchSummary) XXX_Size() int {
+	return xxx_messageInfo_MatchSummary.Size(m)
+}
+func (m *MatchSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatchSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatchSummary proto.InternalMessageInfo
+
+func (m *MatchSummary) GetGamesPlayed() int32 {
+	if m != nil {
+		return m.GamesPlayed
+	}
+	return 0
+}
+
+func (m *MatchSummary) GetSeriesScore() map[string]int32 {
+	if m != nil {
+		return m.SeriesScore
+	}
+	return nil
+}
+
+func (m *MatchSummary) GetRejectedMessages() map[string]int64 {
+	if m != nil {
+		return m.RejectedMessages
+	}
+	return nil
+}
+
+func (m *MatchSummary) GetDroppedMessages() map[string]int64 {
+	if m != nil {
+		return m.DroppedMessages
+	}
+	return nil
+}
+
+// A player's skill rating in one mode of play, by the Glicko-2 rating system.
+type Rating struct {
+	// The rating itself, starting at 1500.
+	Rating float64 `protobuf:"fixed64,1,opt,name=rating,proto3" json:"rating,omitempty"`
+	// How uncertain the rating is. It shrinks as the player plays more games, and grows while they don't play.
+	Deviation float64 `protobuf:"fixed64,2,opt,name=deviation,proto3" json:"deviation,omitempty"`
+	// How consistently the player performs, lower when results match their rating.
+	Volatility float64 `protobuf:"fixed64,3,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	// The number of rated games played.
+	GamesPlayed int32 `protobuf:"varint,4,opt,name=games_played,json=gamesPlayed,proto3" json:"games_played,omitempty"`
+	// The most recent changes to the rating, newest first.
+	Recent               []*RatingChange `protobuf:"bytes,5,rep,name=recent,proto3" json:"recent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Rating) Reset()         { *m = Rating{} }
+func (m *Rating) String() string { return proto.CompactTextString(m) }
+func (*Rating) ProtoMessage()    {}
+func (*Rating) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{38}
+}
+
+func (m *Rating) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rating.Unmarshal(m, b)
+}
+func (m *Rating) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rating.Marshal(b, m, deterministic)
+}
+func (m *Rating) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rating.Merge(m, src)
+}
+func (m *Rating) XXX_Size() int {
+	return xxx_messageInfo_Rating.Size(m)
+}
+func (m *Rating) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rating.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rating proto.InternalMessageInfo
+
+func (m *Rating) GetRating() float64 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *Rating) GetDeviation() float64 {
+	if m != nil {
+		return m.Deviation
+	}
+	return 0
+}
+
+func (m *Rating) GetVolatility() float64 {
+	if m != nil {
+		return m.Volatility
+	}
+	return 0
+}
+
+func (m *Rating) GetGamesPlayed() int32 {
+	if m != nil {
+		return m.GamesPlayed
+	}
+	return 0
+}
+
+func (m *Rating) GetRecent() []*RatingChange {
+	if m != nil {
+		return m.Recent
+	}
+	return nil
+}
+
+// The change to a player's rating from one game.
+type RatingChange struct {
+	// The match the game was played in.
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The user ID of the opponent.
+	OpponentId string `protobuf:"bytes,2,opt,name=opponent_id,json=opponentId,proto3" json:"opponent_id,omitempty"`
+	// The result of the game for the player: 1 for a win, 0.5 for a tie, 0 for a loss.
+	Score float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	// Why the game ended.
+	Reason DoneReason `protobuf:"varint,4,opt,name=reason,proto3,enum=api.DoneReason" json:"reason,omitempty"`
+	// The rating before and after the game.
+	RatingBefore float64 `protobuf:"fixed64,5,opt,name=rating_before,json=ratingBefore,proto3" json:"rating_before,omitempty"`
+	RatingAfter  float64 `protobuf:"fixed64,6,opt,name=rating_after,json=ratingAfter,proto3" json:"rating_after,omitempty"`
+	// The time the game ended.
+	Time                 int64    `protobuf:"varint,7,opt,name=time,proto3" json:"time,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RpcGetMatchRequest) Reset()         { *m = RpcGetMatchRequest{} }
-func (m *RpcGetMatchRequest) String() string { return proto.CompactTextString(m) }
-func (*RpcGetMatchRequest) ProtoMessage()    {}
-func (*RpcGetMatchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+func (m *RatingChange) Reset()         { *m = RatingChange{} }
+func (m *RatingChange) String() string { return proto.CompactTextString(m) }
+func (*RatingChange) ProtoMessage()    {}
+func (*RatingChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{39}
 }
 
-func (m *RpcGetMatchRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RpcGetMatchRequest.Unmarshal(m, b)
+func (m *RatingChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RatingChange.Unmarshal(m, b)
 }
-func (m *RpcGetMatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RpcGetMatchRequest.Marshal(b, m, deterministic)
+func (m *RatingChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RatingChange.Marshal(b, m, deterministic)
 }
-func (m *RpcGetMatchRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RpcGetMatchRequest.Merge(m, src)
+func (m *RatingChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RatingChange.Merge(m, src)
 }
-func (m *RpcGetMatchRequest) XXX_Size() int {
-	return xxx_messageInfo_RpcGetMatchRequest.Size(m)
+func (m *RatingChange) XXX_Size() int {
+	return xxx_messageInfo_RatingChange.Size(m)
 }
-func (m *RpcGetMatchRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_RpcGetMatchRequest.DiscardUnknown(m)
+func (m *RatingChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_RatingChange.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RpcGetMatchRequest proto.InternalMessageInfo
+var xxx_messageInfo_RatingChange proto.InternalMessageInfo
 
-func (m *RpcGetMatchRequest) GetFast() bool {
+func (m *RatingChange) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *RatingChange) GetOpponentId() string {
+	if m != nil {
+		return m.OpponentId
+	}
+	return ""
+}
+
+func (m *RatingChange) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *RatingChange) GetReason() DoneReason {
+	if m != nil {
+		return m.Reason
+	}
+	return DoneReason_DONE_REASON_UNSPECIFIED
+}
+
+func (m *RatingChange) GetRatingBefore() float64 {
+	if m != nil {
+		return m.RatingBefore
+	}
+	return 0
+}
+
+func (m *RatingChange) GetRatingAfter() float64 {
+	if m != nil {
+		return m.RatingAfter
+	}
+	return 0
+}
+
+func (m *RatingChange) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+// Payload for an RPC request to get a player's ratings.
+type RpcGetRatingRequest struct {
+	// The player to get the ratings of. Unset gets the caller's own.
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcGetRatingRequest) Reset()         { *m = RpcGetRatingRequest{} }
+func (m *RpcGetRatingRequest) String() string { return proto.CompactTextString(m) }
+func (*RpcGetRatingRequest) ProtoMessage()    {}
+func (*RpcGetRatingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{40}
+}
+
+func (m *RpcGetRatingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcGetRatingRequest.Unmarshal(m, b)
+}
+func (m *RpcGetRatingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcGetRatingRequest.Marshal(b, m, deterministic)
+}
+func (m *RpcGetRatingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcGetRatingRequest.Merge(m, src)
+}
+func (m *RpcGetRatingRequest) XXX_Size() int {
+	return xxx_messageInfo_RpcGetRatingRequest.Size(m)
+}
+func (m *RpcGetRatingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcGetRatingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcGetRatingRequest proto.InternalMessageInfo
+
+func (m *RpcGetRatingRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+// Payload for an RPC response with a player's ratings.
+type RpcGetRatingResponse struct {
+	// The player the ratings belong to.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The player's rating in fast matches.
+	Fast *Rating `protobuf:"bytes,2,opt,name=fast,proto3" json:"fast,omitempty"`
+	// The player's rating in normal speed matches.
+	Normal               *Rating  `protobuf:"bytes,3,opt,name=normal,proto3" json:"normal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RpcGetRatingResponse) Reset()         { *m = RpcGetRatingResponse{} }
+func (m *RpcGetRatingResponse) String() string { return proto.CompactTextString(m) }
+func (*RpcGetRatingResponse) ProtoMessage()    {}
+func (*RpcGetRatingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{41}
+}
+
+func (m *RpcGetRatingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RpcGetRatingResponse.Unmarshal(m, b)
+}
+func (m *RpcGetRatingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RpcGetRatingResponse.Marshal(b, m, deterministic)
+}
+func (m *RpcGetRatingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RpcGetRatingResponse.Merge(m, src)
+}
+func (m *RpcGetRatingResponse) XXX_Size() int {
+	return xxx_messageInfo_RpcGetRatingResponse.Size(m)
+}
+func (m *RpcGetRatingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RpcGetRatingResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RpcGetRatingResponse proto.InternalMessageInfo
+
+func (m *RpcGetRatingResponse) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *RpcGetRatingResponse) GetFast() *Rating {
 	if m != nil {
 		return m.Fast
 	}
-	return false
+	return nil
 }
 
-// Payload for an RPC response containing match IDs the user can join.
-type RpcGetMatchResponse struct {
-	// One or more matches that fit the user's request.
-	MatchIds             []string `protobuf:"bytes,1,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
+func (m *RpcGetRatingResponse) GetNormal() *Rating {
+	if m != nil {
+		return m.Normal
+	}
+	return nil
+}
+
+// A player's ongoing search for a match, kept between calls to find a match so the range of opponent ratings they're
+// offered can widen the longer they wait.
+type MatchSearch struct {
+	// When the player started searching.
+	StartTime int64 `protobuf:"varint,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// When the player last asked for a match. A search left alone for long enough starts over.
+	LastTime int64 `protobuf:"varint,2,opt,name=last_time,json=lastTime,proto3" json:"last_time,omitempty"`
+	// The match the player created when nobody was found, which they wait in until paired with someone else.
+	MatchId              string   `protobuf:"bytes,3,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RpcGetMatchResponse) Reset()         { *m = RpcGetMatchResponse{} }
-func (m *RpcGetMatchResponse) String() string { return proto.CompactTextString(m) }
-func (*RpcGetMatchResponse) ProtoMessage()    {}
-func (*RpcGetMatchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+func (m *MatchSearch) Reset()         { *m = MatchSearch{} }
+func (m *MatchSearch) String() string { return proto.CompactTextString(m) }
+func (*MatchSearch) ProtoMessage()    {}
+func (*MatchSearch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{42}
 }
 
-func (m *RpcGetMatchResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RpcGetMatchResponse.Unmarshal(m, b)
+func (m *MatchSearch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatchSearch.Unmarshal(m, b)
 }
-func (m *RpcGetMatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RpcGetMatchResponse.Marshal(b, m, deterministic)
+func (m *MatchSearch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatchSearch.Marshal(b, m, deterministic)
 }
-func (m *RpcGetMatchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RpcGetMatchResponse.Merge(m, src)
+func (m *MatchSearch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatchSearch.Merge(m, src)
 }
-func (m *RpcGetMatchResponse) XXX_Size() int {
-	return xxx_messageInfo_RpcGetMatchResponse.Size(m)
+func (m *MatchSearch) XXX_Size() int {
+	return xxx_messageInfo_MatchSearch.Size(m)
 }
-func (m *RpcGetMatchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RpcGetMatchResponse.DiscardUnknown(m)
+func (m *MatchSearch) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatchSearch.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RpcGetMatchResponse proto.InternalMessageInfo
+var xxx_messageInfo_MatchSearch proto.InternalMessageInfo
 
-func (m *RpcGetMatchResponse) GetMatchIds() []string {
+func (m *MatchSearch) GetStartTime() int64 {
 	if m != nil {
-		return m.MatchIds
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *MatchSearch) GetLastTime() int64 {
+	if m != nil {
+		return m.LastTime
+	}
+	return 0
+}
+
+func (m *MatchSearch) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+// A seat held in a match for a player who found it, until they join or the reservation expires.
+type SeatReservation struct {
+	// The secret the player joins with to claim the seat.
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// The player the seat is held for.
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// When the seat is given up if the player hasn't joined.
+	ExpireTime           int64    `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeatReservation) Reset()         { *m = SeatReservation{} }
+func (m *SeatReservation) String() string { return proto.CompactTextString(m) }
+func (*SeatReservation) ProtoMessage()    {}
+func (*SeatReservation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{43}
+}
+
+func (m *SeatReservation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeatReservation.Unmarshal(m, b)
+}
+func (m *SeatReservation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeatReservation.Marshal(b, m, deterministic)
+}
+func (m *SeatReservation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeatReservation.Merge(m, src)
+}
+func (m *SeatReservation) XXX_Size() int {
+	return xxx_messageInfo_SeatReservation.Size(m)
+}
+func (m *SeatReservation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeatReservation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeatReservation proto.InternalMessageInfo
+
+func (m *SeatReservation) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *SeatReservation) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SeatReservation) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+// The seats held in a match, stored per match so players finding it at the same time can't be given the same seat.
+type SeatReservations struct {
+	Reservations         []*SeatReservation `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *SeatReservations) Reset()         { *m = SeatReservations{} }
+func (m *SeatReservations) String() string { return proto.CompactTextString(m) }
+func (*SeatReservations) ProtoMessage()    {}
+func (*SeatReservations) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{44}
+}
+
+func (m *SeatReservations) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeatReservations.Unmarshal(m, b)
+}
+func (m *SeatReservations) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeatReservations.Marshal(b, m, deterministic)
+}
+func (m *SeatReservations) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeatReservations.Merge(m, src)
+}
+func (m *SeatReservations) XXX_Size() int {
+	return xxx_messageInfo_SeatReservations.Size(m)
+}
+func (m *SeatReservations) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeatReservations.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeatReservations proto.InternalMessageInfo
+
+func (m *SeatReservations) GetReservations() []*SeatReservation {
+	if m != nil {
+		return m.Reservations
 	}
 	return nil
 }
 
+// The latest match created for a kind of game, so players who find no match at the same time end up in one match
+// instead of each creating their own.
+type MatchPoolEntry struct {
+	MatchId string `protobuf:"bytes,1,opt,name=match_id,json=matchId,proto3" json:"match_id,omitempty"`
+	// The rating the match advertises.
+	Rating int32 `protobuf:"varint,2,opt,name=rating,proto3" json:"rating,omitempty"`
+	// When the match was created.
+	CreateTime int64 `protobuf:"varint,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// The user ID of the player who created the match.
+	UserId               string   `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MatchPoolEntry) Reset()         { *m = MatchPoolEntry{} }
+func (m *MatchPoolEntry) String() string { return proto.CompactTextString(m) }
+func (*MatchPoolEntry) ProtoMessage()    {}
+func (*MatchPoolEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{45}
+}
+
+func (m *MatchPoolEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatchPoolEntry.Unmarshal(m, b)
+}
+func (m *MatchPoolEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatchPoolEntry.Marshal(b, m, deterministic)
+}
+func (m *MatchPoolEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatchPoolEntry.Merge(m, src)
+}
+func (m *MatchPoolEntry) XXX_Size() int {
+	return xxx_messageInfo_MatchPoolEntry.Size(m)
+}
+func (m *MatchPoolEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatchPoolEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatchPoolEntry proto.InternalMessageInfo
+
+func (m *MatchPoolEntry) GetMatchId() string {
+	if m != nil {
+		return m.MatchId
+	}
+	return ""
+}
+
+func (m *MatchPoolEntry) GetRating() int32 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *MatchPoolEntry) GetCreateTime() int64 {
+	if m != nil {
+		return m.CreateTime
+	}
+	return 0
+}
+
+func (m *MatchPoolEntry) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("api.Mark", Mark_name, Mark_value)
 	proto.RegisterEnum("api.OpCode", OpCode_name, OpCode_value)
+	proto.RegisterEnum("api.DoneReason", DoneReason_name, DoneReason_value)
+	proto.RegisterEnum("api.BotDifficulty", BotDifficulty_name, BotDifficulty_value)
+	proto.RegisterEnum("api.FirstMovePolicy", FirstMovePolicy_name, FirstMovePolicy_value)
+	proto.RegisterEnum("api.FirstMoveReason", FirstMoveReason_name, FirstMoveReason_value)
 	proto.RegisterType((*Start)(nil), "api.Start")
+	proto.RegisterMapType((map[string]int64)(nil), "api.Start.ClocksEntry")
 	proto.RegisterMapType((map[string]Mark)(nil), "api.Start.MarksEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "api.Start.SeriesScoreEntry")
 	proto.RegisterType((*Update)(nil), "api.Update")
+	proto.RegisterMapType((map[string]int64)(nil), "api.Update.ClocksEntry")
 	proto.RegisterMapType((map[string]Mark)(nil), "api.Update.MarksEntry")
 	proto.RegisterType((*Done)(nil), "api.Done")
 	proto.RegisterMapType((map[string]Mark)(nil), "api.Done.MarksEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "api.Done.SeriesScoreEntry")
+	proto.RegisterType((*Snapshot)(nil), "api.Snapshot")
+	proto.RegisterMapType((map[string]int64)(nil), "api.Snapshot.ClocksEntry")
+	proto.RegisterMapType((map[string]Mark)(nil), "api.Snapshot.MarksEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "api.Snapshot.SeriesScoreEntry")
+	proto.RegisterType((*UltimateBoard)(nil), "api.UltimateBoard")
+	proto.RegisterType((*SubBoard)(nil), "api.SubBoard")
+	proto.RegisterType((*SeriesDone)(nil), "api.SeriesDone")
+	proto.RegisterMapType((map[string]int32)(nil), "api.SeriesDone.SeriesScoreEntry")
+	proto.RegisterType((*Resign)(nil), "api.Resign")
+	proto.RegisterType((*DrawOffer)(nil), "api.DrawOffer")
+	proto.RegisterType((*DrawResponse)(nil), "api.DrawResponse")
+	proto.RegisterType((*Rematch)(nil), "api.Rematch")
+	proto.RegisterType((*PlayerEliminated)(nil), "api.PlayerEliminated")
+	proto.RegisterType((*OpponentDisconnected)(nil), "api.OpponentDisconnected")
+	proto.RegisterType((*OpponentReconnected)(nil), "api.OpponentReconnected")
 	proto.RegisterType((*Move)(nil), "api.Move")
 	proto.RegisterType((*RpcFindMatchRequest)(nil), "api.RpcFindMatchRequest")
 	proto.RegisterType((*RpcFindMatchResponse)(nil), "api.RpcFindMatchResponse")
+	proto.RegisterType((*ReplayMove)(nil), "api.ReplayMove")
+	proto.RegisterType((*Replay)(nil), "api.Replay")
+	proto.RegisterMapType((map[string]Mark)(nil), "api.Replay.MarksEntry")
+	proto.RegisterType((*ReplaySummary)(nil), "api.ReplaySummary")
+	proto.RegisterMapType((map[string]Mark)(nil), "api.ReplaySummary.MarksEntry")
+	proto.RegisterType((*RpcGetReplayRequest)(nil), "api.RpcGetReplayRequest")
+	proto.RegisterType((*RpcListReplaysRequest)(nil), "api.RpcListReplaysRequest")
+	proto.RegisterType((*RpcListReplaysResponse)(nil), "api.RpcListReplaysResponse")
 	proto.RegisterType((*RpcGetMatchRequest)(nil), "api.RpcGetMatchRequest")
 	proto.RegisterType((*RpcGetMatchResponse)(nil), "api.RpcGetMatchResponse")
+	proto.RegisterType((*PrivateMatchCode)(nil), "api.PrivateMatchCode")
+	proto.RegisterType((*RpcCreatePrivateMatchRequest)(nil), "api.RpcCreatePrivateMatchRequest")
+	proto.RegisterType((*RpcCreatePrivateMatchResponse)(nil), "api.RpcCreatePrivateMatchResponse")
+	proto.RegisterType((*RpcJoinByCodeRequest)(nil), "api.RpcJoinByCodeRequest")
+	proto.RegisterType((*RpcJoinByCodeResponse)(nil), "api.RpcJoinByCodeResponse")
+	proto.RegisterType((*Challenge)(nil), "api.Challenge")
+	proto.RegisterType((*RpcChallengeFriendRequest)(nil), "api.RpcChallengeFriendRequest")
+	proto.RegisterType((*RpcChallengeFriendResponse)(nil), "api.RpcChallengeFriendResponse")
+	proto.RegisterType((*RpcChallengeRequest)(nil), "api.RpcChallengeRequest")
+	proto.RegisterType((*RpcAcceptChallengeResponse)(nil), "api.RpcAcceptChallengeResponse")
+	proto.RegisterType((*SavedMatch)(nil), "api.SavedMatch")
+	proto.RegisterMapType((map[string]int64)(nil), "api.SavedMatch.ClocksEntry")
+	proto.RegisterMapType((map[string]Mark)(nil), "api.SavedMatch.MarksEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "api.SavedMatch.SeriesScoreEntry")
+	proto.RegisterType((*InputWarning)(nil), "api.InputWarning")
+	proto.RegisterType((*MatchSummary)(nil), "api.MatchSummary")
+	proto.RegisterMapType((map[string]int64)(nil), "api.MatchSummary.DroppedMessagesEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "api.MatchSummary.RejectedMessagesEntry")
+	proto.RegisterMapType((map[string]int32)(nil), "api.MatchSummary.SeriesScoreEntry")
+	proto.RegisterType((*Rating)(nil), "api.Rating")
+	proto.RegisterType((*RatingChange)(nil), "api.RatingChange")
+	proto.RegisterType((*RpcGetRatingRequest)(nil), "api.RpcGetRatingRequest")
+	proto.RegisterType((*RpcGetRatingResponse)(nil), "api.RpcGetRatingResponse")
+	proto.RegisterType((*MatchSearch)(nil), "api.MatchSearch")
+	proto.RegisterType((*SeatReservation)(nil), "api.SeatReservation")
+	proto.RegisterType((*SeatReservations)(nil), "api.SeatReservations")
+	proto.RegisterType((*MatchPoolEntry)(nil), "api.MatchPoolEntry")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 560 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x94, 0x5f, 0x4f, 0xd4, 0x4c,
-	0x18, 0xc5, 0xdf, 0xfe, 0x0d, 0xfb, 0xf0, 0xe2, 0x8e, 0x03, 0x92, 0x06, 0x63, 0xac, 0xbd, 0x30,
-	0x05, 0x05, 0x22, 0x78, 0x61, 0xbc, 0xc3, 0x6d, 0x21, 0x68, 0x96, 0x6e, 0x86, 0xc5, 0x18, 0x6f,
-	0x36, 0xb3, 0xed, 0x08, 0x75, 0xb7, 0x9d, 0xb1, 0x33, 0xa0, 0x24, 0x7e, 0x06, 0xbf, 0x9d, 0xf1,
-	0xeb, 0x98, 0x4e, 0x77, 0xc1, 0x25, 0x9b, 0xc0, 0x85, 0xde, 0x3d, 0xfd, 0xcd, 0x99, 0x93, 0x73,
-	0x9e, 0x4c, 0x0a, 0x2d, 0x2a, 0xf2, 0x2d, 0x51, 0x71, 0xc5, 0xb1, 0x45, 0x45, 0x1e, 0xfc, 0x34,
-	0xc0, 0x39, 0x56, 0xb4, 0x52, 0xf8, 0x31, 0x38, 0x43, 0x4e, 0xab, 0xcc, 0x33, 0x7c, 0x2b, 0xbc,
-	0xb7, 0xd3, 0xda, 0xaa, 0x95, 0x5d, 0x5a, 0x8d, 0x48, 0xc3, 0xf1, 0x33, 0x70, 0x0a, 0x5a, 0x8d,
-	0xa4, 0x67, 0xfa, 0x56, 0xb8, 0xb8, 0xf3, 0x40, 0x0b, 0xf4, 0x5d, 0x2d, 0x93, 0x71, 0xa9, 0xaa,
-	0x4b, 0xd2, 0x68, 0xf0, 0x23, 0xb0, 0xeb, 0xc1, 0xb3, 0x7c, 0x63, 0xd6, 0x4c, 0x63, 0xbc, 0x06,
-	0x0b, 0x19, 0xa3, 0xd9, 0x38, 0x2f, 0x99, 0x67, 0xfb, 0x46, 0x68, 0x91, 0xab, 0xef, 0xb5, 0x0e,
-	0xc0, 0xb5, 0x1f, 0x46, 0x60, 0x8d, 0xd8, 0xa5, 0x67, 0xf8, 0x46, 0xd8, 0x22, 0xf5, 0x58, 0x07,
-	0xbd, 0xa0, 0xe3, 0x73, 0xe6, 0x99, 0x37, 0xbd, 0x1b, 0xfe, 0xda, 0x7c, 0x65, 0x04, 0xbf, 0x0c,
-	0x70, 0x4f, 0x44, 0x46, 0x15, 0xbb, 0xbd, 0xd8, 0x34, 0xab, 0x39, 0x3f, 0xeb, 0xf3, 0x69, 0x6f,
-	0x4b, 0xf7, 0x5e, 0xd5, 0xe7, 0x8d, 0xf7, 0x9c, 0xe2, 0xff, 0xbc, 0xd9, 0x0f, 0x13, 0xec, 0x88,
-	0x97, 0x77, 0xe8, 0xb5, 0x31, 0x1b, 0x7c, 0x45, 0x0b, 0xea, 0xab, 0x73, 0x62, 0x3f, 0x01, 0xf7,
-	0x6b, 0x5e, 0x96, 0xac, 0xd2, 0xa1, 0x67, 0xdc, 0x26, 0x07, 0x78, 0x1d, 0x50, 0x33, 0x0d, 0x04,
-	0x97, 0xb9, 0xca, 0x79, 0x29, 0x3d, 0xc7, 0xb7, 0x42, 0x87, 0xb4, 0x1b, 0xde, 0x9b, 0x62, 0xfc,
-	0x14, 0xda, 0x25, 0xfb, 0xa6, 0x06, 0xa7, 0xb4, 0x60, 0x03, 0x59, 0x3f, 0x11, 0xcf, 0xd5, 0xbb,
-	0x58, 0xaa, 0xf1, 0x01, 0x2d, 0x98, 0x7e, 0x37, 0x7f, 0x67, 0x21, 0x01, 0xd8, 0x5d, 0x7e, 0xc1,
-	0xea, 0xcd, 0x4f, 0x83, 0x69, 0x0f, 0x87, 0x5c, 0x7d, 0x07, 0xeb, 0xb0, 0x4c, 0x44, 0xba, 0x9f,
-	0x97, 0x59, 0x97, 0xaa, 0xf4, 0x8c, 0xb0, 0x2f, 0xe7, 0x4c, 0x2a, 0x8c, 0xc1, 0xfe, 0x44, 0xa5,
-	0xd2, 0xf2, 0x05, 0xa2, 0xe7, 0x60, 0x17, 0x56, 0x66, 0xa5, 0x52, 0xf0, 0x52, 0x32, 0xfc, 0x10,
-	0x5a, 0x45, 0x0d, 0x06, 0x79, 0x26, 0xf5, 0xca, 0x5b, 0x64, 0x41, 0x83, 0xc3, 0x4c, 0x06, 0x21,
-	0x60, 0x22, 0xd2, 0x03, 0xa6, 0x6e, 0xb5, 0xdf, 0xd1, 0x49, 0xae, 0x95, 0x77, 0x70, 0xdf, 0x78,
-	0x09, 0x76, 0x5d, 0x1a, 0xaf, 0x00, 0xea, 0xee, 0x91, 0x77, 0x83, 0x93, 0xa3, 0xe3, 0x5e, 0xdc,
-	0x39, 0xdc, 0x3f, 0x8c, 0x23, 0xf4, 0x1f, 0x06, 0x70, 0x35, 0xfd, 0x80, 0x8c, 0xab, 0x39, 0x41,
-	0xe6, 0xc6, 0x77, 0x70, 0x13, 0xd1, 0xe1, 0x19, 0xc3, 0xab, 0x80, 0x93, 0x5e, 0x27, 0x89, 0xe2,
-	0x1b, 0x37, 0x11, 0xfc, 0x3f, 0xe1, 0xc7, 0xfd, 0x3d, 0xd2, 0x47, 0x06, 0xbe, 0x0f, 0x4b, 0x53,
-	0x65, 0x2f, 0xda, 0xeb, 0xc7, 0xc8, 0xc4, 0x6d, 0x58, 0x9c, 0xa0, 0x28, 0x39, 0x8a, 0x91, 0xf5,
-	0x07, 0xe8, 0x26, 0xef, 0x63, 0x64, 0xe3, 0x65, 0x68, 0x4f, 0x00, 0x89, 0xdf, 0xc6, 0x9d, 0x7e,
-	0x1c, 0x21, 0xe7, 0xcd, 0xee, 0xc7, 0x17, 0xa7, 0xb9, 0x3a, 0x3b, 0x1f, 0x6e, 0xa5, 0xbc, 0xd8,
-	0x3e, 0x63, 0x15, 0xcf, 0xd3, 0x31, 0x1d, 0xca, 0xed, 0x92, 0x8e, 0x68, 0x41, 0x37, 0x45, 0xc5,
-	0x3f, 0xb3, 0x54, 0x6d, 0x2a, 0x56, 0x88, 0x31, 0x55, 0x6c, 0x9b, 0x8a, 0x7c, 0xe8, 0xea, 0x3f,
-	0xd3, 0xee, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x5d, 0x96, 0xe8, 0xa6, 0x04, 0x00, 0x00,
+	// 3123 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0xcd, 0x72, 0x1b, 0xc7,
+	0xf1, 0xff, 0x2f, 0x16, 0x9f, 0x0d, 0x80, 0x58, 0x8d, 0x40, 0x6a, 0x45, 0x8a, 0x12, 0x05, 0x95,
+	0x6d, 0x9a, 0x7f, 0x5b, 0xb2, 0xa5, 0x43, 0xec, 0x54, 0xc5, 0x65, 0x10, 0x58, 0xca, 0x70, 0x40,
+	0x00, 0x1e, 0x80, 0x91, 0x94, 0x54, 0x65, 0x6b, 0x09, 0x0c, 0xc9, 0x0d, 0x81, 0x5d, 0x64, 0x77,
+	0x49, 0x89, 0xae, 0xca, 0x2d, 0x0f, 0xe0, 0x53, 0xce, 0x39, 0xa7, 0x72, 0x4e, 0xe5, 0x1d, 0x52,
+	0xc9, 0x21, 0x79, 0x85, 0xe4, 0xe2, 0x43, 0x0e, 0x79, 0x82, 0xd4, 0x7c, 0x2c, 0x30, 0xbb, 0xf8,
+	0x12, 0x2d, 0x39, 0xa9, 0xdc, 0x76, 0xba, 0x67, 0x7a, 0x7a, 0xba, 0x7f, 0xd3, 0xdd, 0xd3, 0x00,
+	0xe4, 0xac, 0xb1, 0xfd, 0x70, 0xec, 0xb9, 0x81, 0x8b, 0x54, 0x6b, 0x6c, 0x57, 0xfe, 0x94, 0x82,
+	0x54, 0x37, 0xb0, 0xbc, 0x00, 0xdd, 0x83, 0xd4, 0xb1, 0x6b, 0x79, 0x03, 0x5d, 0xd9, 0x51, 0x77,
+	0xd7, 0x1e, 0xe7, 0x1e, 0xd2, 0x99, 0x87, 0x96, 0x77, 0x8e, 0x39, 0x1d, 0xfd, 0x3f, 0xa4, 0x46,
+	0x96, 0x77, 0xee, 0xeb, 0x89, 0x1d, 0x75, 0x37, 0xff, 0x78, 0x9d, 0x4d, 0x60, 0x6b, 0xd9, 0x34,
+	0xdf, 0x70, 0x02, 0xef, 0x0a, 0xf3, 0x39, 0x68, 0x1b, 0x92, 0xf4, 0x43, 0x57, 0x77, 0x94, 0xa8,
+	0x30, 0x46, 0x46, 0x9b, 0x90, 0x1d, 0x10, 0x6b, 0x30, 0xb4, 0x1d, 0xa2, 0x27, 0x77, 0x94, 0x5d,
+	0x15, 0x4f, 0xc6, 0x68, 0x1b, 0x80, 0x6d, 0x68, 0xfa, 0xf6, 0xd7, 0x44, 0x4f, 0xed, 0x28, 0xbb,
+	0x29, 0x9c, 0x63, 0x94, 0xae, 0xfd, 0x35, 0x63, 0xbf, 0xb4, 0x1d, 0x73, 0x48, 0x9c, 0xd3, 0xe0,
+	0x4c, 0x4f, 0x73, 0xf6, 0x4b, 0xdb, 0x69, 0x32, 0x02, 0xfa, 0x0c, 0x0a, 0x3e, 0xf1, 0x6c, 0xe2,
+	0x9b, 0x7e, 0xdf, 0xf5, 0x88, 0x9e, 0x61, 0xca, 0x6e, 0x49, 0xca, 0x76, 0x19, 0xbb, 0x4b, 0xb9,
+	0x5c, 0xe5, 0xbc, 0x3f, 0xa5, 0xa0, 0x07, 0x50, 0x14, 0xeb, 0xc5, 0x0e, 0x59, 0xb6, 0x83, 0x10,
+	0x2a, 0x36, 0xf9, 0x1c, 0x6e, 0x9c, 0xd8, 0x9e, 0x1f, 0x98, 0x23, 0xf7, 0x92, 0x98, 0x1e, 0xb1,
+	0x7c, 0xd7, 0xd1, 0x73, 0xec, 0xa8, 0x65, 0xb6, 0xd3, 0x01, 0xe5, 0x1e, 0xba, 0x97, 0x04, 0x33,
+	0x1e, 0x2e, 0x9d, 0x44, 0x09, 0xe8, 0x21, 0xa4, 0xfb, 0x43, 0xb7, 0x7f, 0xee, 0xeb, 0xc0, 0x14,
+	0xdc, 0x90, 0x14, 0xac, 0x31, 0x06, 0xd7, 0x4d, 0xcc, 0xa2, 0x06, 0xf3, 0xc9, 0x2f, 0x2f, 0x88,
+	0xd3, 0x27, 0x7a, 0x9e, 0x1b, 0x2c, 0x1c, 0xa3, 0x87, 0x90, 0xbd, 0x18, 0x06, 0xf6, 0xc8, 0x0a,
+	0x88, 0x5e, 0xd8, 0x51, 0x76, 0xf3, 0x8f, 0x11, 0x93, 0x76, 0x24, 0x88, 0xfb, 0xd4, 0x76, 0x78,
+	0x32, 0x07, 0xe9, 0x90, 0xb9, 0xb4, 0x3c, 0xdb, 0x72, 0x02, 0xbd, 0xb8, 0xa3, 0xec, 0xe6, 0x70,
+	0x38, 0xdc, 0xac, 0x01, 0x4c, 0x5d, 0x89, 0x34, 0x50, 0xcf, 0xc9, 0x95, 0xae, 0xb0, 0x39, 0xf4,
+	0x93, 0x62, 0xe4, 0xd2, 0x1a, 0x5e, 0x10, 0x3d, 0x11, 0x77, 0x2b, 0xa7, 0xff, 0x30, 0xf1, 0x89,
+	0xb2, 0xf9, 0x19, 0x68, 0x71, 0x13, 0xcf, 0x11, 0x55, 0x96, 0x45, 0xa5, 0xe4, 0xf5, 0x9f, 0x42,
+	0x5e, 0xb2, 0xc0, 0xaa, 0xa5, 0xaa, 0xb4, 0xb4, 0xf2, 0x67, 0x15, 0xd2, 0x47, 0xe3, 0x01, 0x3d,
+	0xe4, 0x4a, 0x38, 0x87, 0x08, 0x4d, 0xcc, 0x47, 0xe8, 0x07, 0x21, 0xda, 0x55, 0xc9, 0x3f, 0x5c,
+	0xf6, 0x1c, 0xb8, 0x7f, 0x7f, 0x78, 0x7e, 0x34, 0x01, 0x0a, 0x47, 0xf2, 0x2d, 0x59, 0x91, 0x55,
+	0x48, 0xc9, 0x2e, 0x41, 0x4a, 0x6e, 0x35, 0x52, 0xde, 0x0e, 0x1e, 0xde, 0xc0, 0x9f, 0xdf, 0xa4,
+	0x20, 0x59, 0x77, 0x9d, 0xd7, 0xf0, 0xe6, 0x5e, 0xd4, 0x5d, 0xfc, 0x16, 0xd2, 0xa5, 0x73, 0x9c,
+	0x75, 0x1f, 0xd2, 0x2f, 0x6d, 0xc7, 0x21, 0x1e, 0x73, 0x55, 0x44, 0x9a, 0x60, 0xa0, 0xf7, 0x41,
+	0xe3, 0x5f, 0xe6, 0xd8, 0xf5, 0xed, 0xc0, 0x76, 0x1d, 0x5f, 0x4f, 0xed, 0xa8, 0xbb, 0x29, 0x5c,
+	0xe2, 0xf4, 0x4e, 0x48, 0x46, 0xef, 0x42, 0xc9, 0x21, 0xaf, 0x02, 0xf3, 0xd4, 0x1a, 0x11, 0xd3,
+	0xa7, 0x17, 0x98, 0x39, 0x51, 0xc5, 0x45, 0x4a, 0x7e, 0x6a, 0x8d, 0x08, 0x8f, 0xaf, 0x51, 0x18,
+	0x64, 0x96, 0xc3, 0x20, 0x1b, 0x87, 0xc1, 0x8f, 0x62, 0x61, 0x2d, 0xc7, 0x8e, 0xb9, 0x39, 0x3d,
+	0xe6, 0x35, 0xa3, 0x1a, 0xcc, 0x89, 0x6a, 0xef, 0x41, 0x5a, 0x84, 0xb2, 0x3c, 0xb3, 0x4b, 0x69,
+	0x22, 0x5d, 0x44, 0x31, 0xc1, 0xa6, 0xd6, 0xf1, 0xc8, 0xc8, 0x0a, 0xfa, 0x67, 0xe6, 0x04, 0xf5,
+	0x05, 0x76, 0xe6, 0x92, 0xa0, 0xd7, 0x05, 0x39, 0x82, 0xc6, 0xe2, 0x12, 0x34, 0xae, 0xfd, 0xa7,
+	0xd0, 0xf8, 0x86, 0xd1, 0xa9, 0xf2, 0xcf, 0x14, 0x64, 0xbb, 0x8e, 0x35, 0xf6, 0xcf, 0xdc, 0x20,
+	0x72, 0x3a, 0x25, 0x76, 0x3a, 0x1d, 0x32, 0xe3, 0xa1, 0x75, 0x65, 0x3b, 0xa7, 0x4c, 0x48, 0x16,
+	0x87, 0xc3, 0x29, 0x98, 0xd5, 0x05, 0x60, 0x7e, 0x18, 0x82, 0x39, 0xc9, 0xbc, 0xac, 0xf3, 0xdc,
+	0x20, 0x36, 0x5d, 0x92, 0x6c, 0x53, 0xab, 0x93, 0x6d, 0x7a, 0x69, 0x70, 0xba, 0x2e, 0x2a, 0x3f,
+	0x9e, 0x04, 0x27, 0x8e, 0xc7, 0xdb, 0x51, 0x4d, 0xe7, 0x85, 0xa7, 0x6a, 0x0c, 0xc8, 0x3c, 0xfd,
+	0xdd, 0x8d, 0x2e, 0xbc, 0x26, 0x98, 0xf3, 0x73, 0xc0, 0xbc, 0x0d, 0xc9, 0x81, 0xeb, 0x84, 0x09,
+	0x31, 0x37, 0x85, 0x32, 0x23, 0x47, 0xb0, 0x57, 0xbc, 0x5e, 0xce, 0x5c, 0x8b, 0xe4, 0x4c, 0x74,
+	0x17, 0x80, 0x0c, 0xed, 0x91, 0xed, 0x58, 0x01, 0x19, 0xe8, 0xa5, 0x1d, 0x75, 0x37, 0x87, 0x25,
+	0xca, 0x7f, 0x3b, 0x86, 0xbe, 0x31, 0xe0, 0x4f, 0xa1, 0x18, 0x31, 0x0a, 0xfa, 0x00, 0xc0, 0xbf,
+	0x38, 0x36, 0x19, 0x48, 0x7c, 0x16, 0x90, 0xf3, 0x8f, 0x8b, 0xdc, 0x7f, 0x17, 0xc7, 0xdc, 0x6e,
+	0x39, 0x5f, 0x7c, 0xf9, 0x68, 0x17, 0x34, 0xab, 0x1f, 0xd8, 0x97, 0xc4, 0x9c, 0x2c, 0x12, 0x7b,
+	0xac, 0x71, 0x7a, 0xb8, 0xa8, 0x72, 0x02, 0xd9, 0xf0, 0x9b, 0x1a, 0xa5, 0x4f, 0x86, 0x43, 0x7f,
+	0x4e, 0xbc, 0x67, 0x74, 0x29, 0x86, 0x27, 0x16, 0xc5, 0xf0, 0x0d, 0x06, 0x4e, 0x9f, 0x0c, 0x58,
+	0x11, 0x9a, 0xc5, 0x62, 0x54, 0xf9, 0x56, 0x01, 0xe0, 0x16, 0x61, 0xa9, 0x65, 0x63, 0x22, 0x89,
+	0x9b, 0x23, 0x5c, 0x5e, 0x8b, 0x01, 0x95, 0x57, 0xbd, 0x3b, 0xfc, 0xa0, 0x93, 0xe5, 0xd7, 0x85,
+	0xaa, 0x3a, 0x07, 0xaa, 0x72, 0x14, 0x49, 0x46, 0xa3, 0xc8, 0x1b, 0x7b, 0x2f, 0x0b, 0x69, 0x4c,
+	0x7c, 0xfb, 0xd4, 0xa9, 0x7c, 0x0e, 0xb9, 0xba, 0x67, 0xbd, 0x6c, 0x9f, 0x9c, 0x10, 0x0f, 0xdd,
+	0x82, 0xcc, 0x85, 0x4f, 0x3c, 0xd3, 0x1e, 0x84, 0xa7, 0xa6, 0xc3, 0xc6, 0x20, 0xa2, 0x4b, 0x22,
+	0xaa, 0x4b, 0xe5, 0x67, 0x50, 0xa0, 0x12, 0x30, 0xf1, 0xc7, 0xae, 0xe3, 0x33, 0xcb, 0x59, 0xfd,
+	0x3e, 0x19, 0x07, 0x4c, 0x46, 0x16, 0x8b, 0x91, 0x2c, 0x3c, 0xb1, 0x50, 0xb8, 0x1a, 0x13, 0xfe,
+	0x1c, 0x32, 0x98, 0xe7, 0x8e, 0xc5, 0xca, 0xe9, 0x90, 0x09, 0xec, 0x11, 0x71, 0x2f, 0x82, 0x30,
+	0xa4, 0x8a, 0xe1, 0x52, 0xc9, 0x7f, 0x53, 0x40, 0xeb, 0x0c, 0xad, 0x2b, 0xe2, 0x19, 0x93, 0x5b,
+	0xb9, 0x78, 0x8f, 0x15, 0x65, 0xe1, 0x34, 0x47, 0xaa, 0xcb, 0x73, 0xe4, 0xbb, 0x90, 0x63, 0x65,
+	0x01, 0x13, 0x36, 0x53, 0x67, 0x64, 0x29, 0xef, 0x30, 0x1e, 0x9c, 0x53, 0xb1, 0xe0, 0x2c, 0x9f,
+	0x2a, 0x1d, 0x3b, 0xd5, 0x29, 0x94, 0xdb, 0xe3, 0xb1, 0xeb, 0x10, 0x27, 0xa8, 0xdb, 0x7e, 0xdf,
+	0x75, 0x1c, 0xd2, 0x5f, 0x7a, 0x30, 0x79, 0xa3, 0xc4, 0x92, 0x8d, 0xe2, 0xe6, 0x3b, 0x81, 0x9b,
+	0xe1, 0x46, 0x98, 0x7c, 0x8f, 0xfb, 0x54, 0x21, 0x49, 0xdf, 0x47, 0x74, 0x4e, 0x58, 0x73, 0x31,
+	0xc9, 0x29, 0x3c, 0x19, 0xaf, 0x70, 0x4e, 0xe5, 0x0f, 0x09, 0xb8, 0x89, 0xc7, 0xfd, 0x03, 0xdb,
+	0x19, 0x1c, 0x52, 0x24, 0x61, 0x2a, 0xdb, 0x0f, 0x10, 0x82, 0xe4, 0x89, 0xe5, 0x87, 0x30, 0x65,
+	0xdf, 0xb1, 0xc4, 0x97, 0x58, 0x9e, 0xf8, 0xd4, 0x78, 0xe2, 0xfb, 0x14, 0xd6, 0x8e, 0xdd, 0xc0,
+	0x1c, 0xd8, 0x27, 0x27, 0x76, 0xff, 0x62, 0x18, 0x5c, 0x09, 0x17, 0xf3, 0x24, 0xb2, 0xef, 0x06,
+	0xf5, 0x09, 0x07, 0x17, 0x8f, 0xe5, 0xe1, 0x6c, 0x48, 0x48, 0xcd, 0x09, 0x09, 0xf7, 0xa1, 0x40,
+	0xa1, 0x6d, 0xf6, 0x5d, 0x27, 0xf0, 0xdc, 0x21, 0xf3, 0x7e, 0x0e, 0xe7, 0x29, 0xad, 0xc6, 0x49,
+	0xd4, 0x4e, 0x93, 0x0c, 0x96, 0x61, 0x07, 0x9b, 0x9b, 0xad, 0xb2, 0xd1, 0x6c, 0x55, 0x86, 0x94,
+	0x4f, 0xac, 0xc0, 0x67, 0xe5, 0x7f, 0x0a, 0xf3, 0x41, 0xe5, 0x08, 0xca, 0x51, 0xbb, 0x89, 0x1b,
+	0xbe, 0x05, 0x39, 0x5e, 0xe6, 0xd9, 0x22, 0xd2, 0xe7, 0x70, 0x96, 0x11, 0x1a, 0x03, 0x1f, 0xed,
+	0x40, 0xde, 0x23, 0x3e, 0xf1, 0x2e, 0x2d, 0xe6, 0x2b, 0x7e, 0xd5, 0x65, 0x52, 0x25, 0x00, 0xc0,
+	0x84, 0x56, 0x3d, 0xcc, 0xb1, 0xdf, 0xf5, 0xca, 0xc9, 0x80, 0x50, 0x63, 0x80, 0x40, 0x90, 0x0c,
+	0xec, 0xfe, 0xb9, 0x08, 0x9b, 0xec, 0xbb, 0xf2, 0xaf, 0x24, 0xa4, 0xf9, 0xb6, 0x54, 0x7f, 0x8f,
+	0x7d, 0x4d, 0x37, 0xcd, 0x72, 0x42, 0x63, 0x80, 0x6e, 0x43, 0x36, 0x3c, 0x9c, 0x50, 0x3e, 0x23,
+	0xce, 0x16, 0x03, 0x87, 0xba, 0x1c, 0x1c, 0xc9, 0x38, 0x38, 0x26, 0x4f, 0xc7, 0x94, 0xf4, 0x74,
+	0xe4, 0x1a, 0xcd, 0x29, 0xde, 0xde, 0x81, 0x14, 0xed, 0x22, 0xf8, 0x7a, 0x9a, 0xcd, 0x2e, 0x49,
+	0xb3, 0x59, 0xbf, 0x80, 0x73, 0xa5, 0x84, 0x97, 0xb9, 0xce, 0xa3, 0x25, 0x3b, 0xff, 0xd1, 0xb2,
+	0x05, 0x39, 0x6a, 0x2b, 0xd3, 0x0b, 0x5f, 0x82, 0x29, 0x9c, 0xa5, 0x04, 0x4c, 0xd1, 0xb3, 0x0d,
+	0xc0, 0xde, 0x31, 0x26, 0x85, 0x1b, 0x7b, 0x29, 0xa8, 0x38, 0xc7, 0x28, 0x3d, 0x7b, 0x44, 0xa8,
+	0xdd, 0x88, 0x33, 0xe0, 0x4c, 0xde, 0x8a, 0xc8, 0x10, 0x67, 0xc0, 0x58, 0xd3, 0xe8, 0x58, 0x58,
+	0x1e, 0x1d, 0xe7, 0x36, 0x50, 0x8a, 0xd7, 0x69, 0xa0, 0x20, 0x48, 0xfa, 0x84, 0x0c, 0x58, 0x35,
+	0xa6, 0x62, 0xf6, 0x1d, 0xb9, 0x12, 0xa5, 0xc5, 0x57, 0x42, 0x7b, 0xfb, 0x4d, 0x8f, 0xca, 0xdf,
+	0x15, 0x28, 0x72, 0xa7, 0x75, 0x2f, 0x46, 0x23, 0xcb, 0x5b, 0x81, 0xbd, 0x27, 0xd1, 0x5e, 0xda,
+	0xb6, 0xe4, 0x74, 0xb1, 0x7e, 0xe9, 0xbb, 0x55, 0x5d, 0x04, 0x01, 0xd9, 0x37, 0xc9, 0x88, 0x6f,
+	0xde, 0xce, 0x31, 0x1f, 0xb3, 0x00, 0xfb, 0x94, 0x04, 0x5c, 0xd7, 0x30, 0xc0, 0x2e, 0x3b, 0x6b,
+	0xc5, 0x80, 0x75, 0x3c, 0xee, 0x37, 0x6d, 0x5f, 0x2c, 0xf2, 0xc3, 0x55, 0x65, 0x48, 0xd1, 0x84,
+	0x1c, 0x88, 0x30, 0xcf, 0x07, 0xac, 0x6c, 0xbb, 0xf0, 0x7c, 0xd7, 0x0b, 0x8b, 0x07, 0x3e, 0xaa,
+	0xfc, 0x1c, 0x36, 0xe2, 0x62, 0x44, 0x94, 0xfa, 0x00, 0x32, 0x7c, 0xb3, 0xb0, 0x1a, 0x45, 0xb3,
+	0xe6, 0xc4, 0xe1, 0x94, 0x85, 0xf2, 0x77, 0x01, 0xf1, 0xa3, 0xad, 0x4a, 0x1d, 0x53, 0x23, 0xbc,
+	0x7e, 0xb0, 0xac, 0xb4, 0x40, 0xeb, 0x78, 0xf6, 0xa5, 0x15, 0x10, 0xb6, 0xa8, 0xe6, 0x0e, 0x48,
+	0x24, 0x00, 0x29, 0xd1, 0x00, 0x74, 0x0f, 0xf2, 0xe4, 0xd5, 0xd8, 0xf6, 0x08, 0x77, 0x25, 0xcf,
+	0xa3, 0xc0, 0x49, 0xd4, 0x9b, 0x95, 0x3f, 0x2a, 0x70, 0x07, 0x8f, 0xfb, 0x35, 0x8f, 0x58, 0x01,
+	0x91, 0x25, 0x7f, 0x7f, 0x39, 0x6f, 0x26, 0x71, 0x25, 0x5f, 0x23, 0x71, 0xa5, 0x66, 0x12, 0x57,
+	0xc5, 0x85, 0xed, 0x05, 0x9a, 0x0b, 0x43, 0x2e, 0xb1, 0x0b, 0x82, 0x64, 0xdf, 0x1d, 0x10, 0xe1,
+	0x3a, 0xf6, 0x1d, 0xb7, 0x95, 0x3a, 0x63, 0xab, 0x3d, 0x96, 0xdd, 0xbe, 0x74, 0x6d, 0x67, 0xff,
+	0x8a, 0x1a, 0x5e, 0x32, 0x11, 0x13, 0xa6, 0x4c, 0x85, 0x55, 0x1e, 0xc3, 0x7a, 0x6c, 0xee, 0x4a,
+	0xa5, 0x2a, 0x43, 0xc8, 0xd5, 0xce, 0xac, 0x21, 0xb5, 0xca, 0x52, 0xe5, 0x1f, 0x40, 0xb1, 0x1f,
+	0xce, 0x93, 0xaa, 0xe3, 0xc2, 0x94, 0xd8, 0x18, 0xac, 0x3e, 0xcd, 0x5f, 0x14, 0xb8, 0x4d, 0xed,
+	0x17, 0x2e, 0x3a, 0xf0, 0x6c, 0xe2, 0x0c, 0xc2, 0x33, 0x2d, 0x4c, 0xb2, 0x21, 0x1e, 0x12, 0x0b,
+	0xf1, 0x70, 0xdd, 0x34, 0xf7, 0x96, 0x0a, 0x99, 0xca, 0x73, 0xd8, 0x9c, 0x77, 0x9e, 0xd5, 0x60,
+	0x58, 0x79, 0x49, 0x3e, 0x82, 0x9b, 0xb2, 0xe4, 0xd0, 0x46, 0x4b, 0x5c, 0xf9, 0x03, 0xa6, 0x4b,
+	0x95, 0xbd, 0x63, 0xa4, 0x75, 0xab, 0x31, 0xf0, 0x3b, 0x00, 0xe8, 0x5a, 0x97, 0x84, 0x17, 0x50,
+	0x2b, 0x20, 0xfc, 0x96, 0x1d, 0x31, 0x5b, 0x8c, 0xa6, 0xbe, 0x73, 0x31, 0x9a, 0x7e, 0x0d, 0x1f,
+	0x66, 0x66, 0x8b, 0xd1, 0x68, 0x3e, 0x1f, 0xbb, 0x43, 0xbb, 0x7f, 0xa5, 0x67, 0xe7, 0xe5, 0xf3,
+	0x0e, 0xe3, 0x49, 0xf9, 0x9c, 0x13, 0xe4, 0x76, 0x59, 0x6e, 0x41, 0xbb, 0x0c, 0x16, 0xb4, 0xcb,
+	0x3e, 0x0a, 0x93, 0x69, 0x5e, 0x6a, 0x8a, 0x4e, 0x9d, 0xb1, 0xa4, 0x61, 0x56, 0x98, 0x5f, 0x71,
+	0x7e, 0x02, 0x7a, 0xf8, 0x64, 0x31, 0x69, 0x43, 0xd3, 0x76, 0x6c, 0xe7, 0xd4, 0xa4, 0xf5, 0x91,
+	0x2f, 0x9a, 0x98, 0x1b, 0x21, 0x1f, 0x87, 0xec, 0x1e, 0xe5, 0xa2, 0x27, 0x93, 0x86, 0xd8, 0x9a,
+	0xfc, 0xbb, 0xd3, 0x54, 0x97, 0x79, 0x2d, 0xb1, 0x78, 0xa7, 0xa1, 0x24, 0x77, 0x1a, 0xa6, 0x4b,
+	0x97, 0x77, 0x1a, 0xee, 0x43, 0x81, 0x76, 0xa0, 0x7d, 0x93, 0x9a, 0x8d, 0x0c, 0x58, 0x91, 0x93,
+	0xc2, 0x79, 0x46, 0x63, 0xaf, 0xdf, 0x81, 0x54, 0x3f, 0xdc, 0x58, 0x54, 0x3f, 0x3c, 0xa0, 0x05,
+	0x1c, 0x95, 0xa0, 0x23, 0xd6, 0x14, 0xcb, 0x4b, 0x99, 0x14, 0x0b, 0x16, 0x85, 0x23, 0x15, 0x2b,
+	0x0c, 0x72, 0x93, 0xd7, 0x87, 0x94, 0xc2, 0x6d, 0x20, 0x57, 0x61, 0xe5, 0x58, 0x15, 0x36, 0xaf,
+	0x1b, 0xb4, 0x3e, 0xaf, 0x1b, 0x24, 0xd7, 0x6b, 0x1b, 0x0b, 0x9e, 0x30, 0xb7, 0xa4, 0x27, 0x4c,
+	0xac, 0x0d, 0xa7, 0xc7, 0xdb, 0x70, 0x74, 0x95, 0xc7, 0x58, 0xb7, 0x99, 0x4a, 0x7c, 0x80, 0x3e,
+	0x82, 0xb2, 0x17, 0x3e, 0x6a, 0xcd, 0x97, 0xb6, 0x33, 0x70, 0x5f, 0x9a, 0x3e, 0xe9, 0xeb, 0x9b,
+	0x4c, 0x34, 0x9a, 0xf0, 0x9e, 0x31, 0x56, 0x97, 0xf4, 0xd1, 0x3b, 0xb0, 0x36, 0xb2, 0x5e, 0x99,
+	0xfe, 0x98, 0xf4, 0x03, 0x2b, 0x70, 0x3d, 0x5f, 0xdf, 0x62, 0x73, 0x8b, 0x23, 0xeb, 0x55, 0x77,
+	0x42, 0x44, 0x3b, 0x50, 0xa0, 0x77, 0xf2, 0xa5, 0x65, 0x07, 0x4c, 0xe0, 0x1d, 0x36, 0x09, 0x8e,
+	0xdd, 0xe0, 0x99, 0x65, 0x07, 0x54, 0xd0, 0x06, 0xa4, 0x3d, 0x2b, 0xa0, 0x78, 0xdf, 0x66, 0x3c,
+	0x31, 0xa2, 0x8e, 0x1c, 0xf3, 0xac, 0x68, 0xb2, 0xec, 0x74, 0x97, 0xdf, 0x36, 0x41, 0xa3, 0x39,
+	0xe9, 0x7f, 0xbe, 0xa5, 0x38, 0x80, 0x42, 0xc3, 0x19, 0x5f, 0x04, 0xcf, 0x2c, 0x8f, 0x5e, 0x1d,
+	0xf4, 0x31, 0xac, 0x53, 0x9b, 0x8e, 0x88, 0xef, 0x5b, 0xa7, 0x14, 0xc2, 0xc4, 0x63, 0xe0, 0x12,
+	0x85, 0x21, 0x1a, 0x59, 0xaf, 0x0e, 0x05, 0xaf, 0x43, 0x3c, 0x8a, 0x32, 0x6a, 0x25, 0x3f, 0xf0,
+	0xec, 0x73, 0x16, 0xb9, 0x4e, 0x02, 0xb1, 0x47, 0x5e, 0xd0, 0x9a, 0xe4, 0x24, 0xa8, 0x7c, 0xab,
+	0x42, 0x81, 0xdd, 0x9c, 0xb0, 0x22, 0x8f, 0x5f, 0x11, 0x65, 0xf6, 0x8a, 0x18, 0x73, 0x9b, 0x7e,
+	0x15, 0x61, 0xc0, 0xa9, 0xac, 0x15, 0x97, 0xb1, 0x07, 0x37, 0x3c, 0xf2, 0x0b, 0xd6, 0x28, 0x99,
+	0x9c, 0x4a, 0xfc, 0x32, 0xf5, 0xde, 0xac, 0x2c, 0x2c, 0xa6, 0x86, 0x67, 0xe4, 0x02, 0x35, 0x2f,
+	0x46, 0x46, 0x5f, 0x81, 0x36, 0xf0, 0xdc, 0xf1, 0x58, 0x16, 0xca, 0x7f, 0x21, 0x78, 0x77, 0x56,
+	0x68, 0x9d, 0xcf, 0x8c, 0xca, 0x2c, 0x0d, 0xa2, 0xd4, 0x37, 0xfe, 0xad, 0xb6, 0x06, 0xeb, 0x73,
+	0xb5, 0xbf, 0x16, 0x9c, 0xf6, 0xa1, 0x3c, 0x4f, 0xdb, 0x6b, 0xfd, 0x52, 0xf8, 0x7b, 0x05, 0xd2,
+	0x98, 0x5f, 0xa0, 0xe9, 0xc5, 0xa2, 0x2b, 0x95, 0xc9, 0xc5, 0xba, 0x03, 0xb9, 0x01, 0xb9, 0xb4,
+	0xa7, 0xdd, 0x0a, 0x05, 0x4f, 0x09, 0x34, 0x7e, 0x5c, 0xba, 0x43, 0x2b, 0xb0, 0x87, 0x76, 0x70,
+	0xc5, 0x52, 0xb0, 0x82, 0x25, 0xca, 0x0c, 0x78, 0x92, 0xb3, 0xe0, 0x79, 0x9f, 0x06, 0xcf, 0x3e,
+	0x71, 0x02, 0xf1, 0xf0, 0xbf, 0xc1, 0x83, 0x27, 0xdb, 0xbd, 0x76, 0x66, 0xd1, 0x4a, 0x42, 0x4c,
+	0xa8, 0xfc, 0x43, 0x81, 0x82, 0xcc, 0x58, 0x51, 0xe6, 0xb8, 0xa2, 0x01, 0x37, 0x2d, 0x1a, 0x21,
+	0x24, 0x35, 0x58, 0x68, 0xe3, 0x68, 0xe5, 0x5a, 0xf3, 0x81, 0xf4, 0x16, 0x4f, 0x2e, 0x7f, 0x8b,
+	0x3f, 0x80, 0x22, 0xb7, 0x90, 0x79, 0x4c, 0x4e, 0xa8, 0x98, 0x14, 0x13, 0x53, 0xe0, 0xc4, 0x7d,
+	0x46, 0xa3, 0xc7, 0x17, 0x93, 0xac, 0x93, 0x80, 0x78, 0xac, 0x4e, 0x50, 0x70, 0x9e, 0xd3, 0xaa,
+	0x94, 0xc4, 0x7b, 0x31, 0x23, 0xde, 0x8c, 0x62, 0xbd, 0x98, 0x11, 0xa9, 0x3c, 0x9c, 0xbc, 0x17,
+	0xd9, 0xc4, 0x55, 0x55, 0x6a, 0xe5, 0x02, 0xca, 0xd1, 0xf9, 0xa2, 0xf2, 0x5a, 0xb4, 0x00, 0xdd,
+	0x93, 0xaa, 0xa9, 0x49, 0xba, 0xe2, 0x6b, 0x19, 0x83, 0x66, 0x34, 0xc7, 0xf5, 0x46, 0xd6, 0x50,
+	0x57, 0x67, 0xa7, 0x08, 0x56, 0x65, 0x00, 0x79, 0x7e, 0x79, 0x88, 0xe5, 0xf5, 0xcf, 0x62, 0x0d,
+	0x10, 0x25, 0xde, 0x00, 0xd9, 0x82, 0xdc, 0xd0, 0xf2, 0x03, 0xb9, 0xea, 0xcc, 0x52, 0x42, 0xd8,
+	0x1d, 0x99, 0x38, 0x52, 0x8d, 0xd6, 0x88, 0x16, 0x94, 0xba, 0xc4, 0x0a, 0xf0, 0xb4, 0x43, 0x46,
+	0x5d, 0x17, 0xb8, 0xe7, 0xc4, 0x11, 0xa7, 0xe2, 0x83, 0xc5, 0x0d, 0xf4, 0x95, 0x8f, 0x83, 0x26,
+	0x68, 0xb1, 0x2d, 0x7c, 0xf4, 0x09, 0x14, 0xa4, 0xa6, 0x5c, 0xf8, 0x46, 0x2e, 0x8b, 0x1f, 0x32,
+	0x22, 0x93, 0x71, 0x64, 0x66, 0xe5, 0x57, 0xb0, 0xc6, 0xcc, 0xd2, 0x71, 0xdd, 0x21, 0xbf, 0x92,
+	0x4b, 0x60, 0x3a, 0xbd, 0x76, 0x89, 0x48, 0x3e, 0xbb, 0x07, 0xf9, 0x3e, 0x7b, 0xeb, 0x45, 0x74,
+	0xe6, 0x24, 0x66, 0x31, 0xe9, 0xb4, 0x49, 0xf9, 0xb4, 0x7b, 0xcf, 0x21, 0xc9, 0x5a, 0xe4, 0x65,
+	0xd0, 0x0e, 0xab, 0xf8, 0xc7, 0xe6, 0x51, 0xab, 0xdb, 0x31, 0x6a, 0x8d, 0x83, 0x86, 0x51, 0xd7,
+	0xfe, 0x0f, 0x01, 0xa4, 0x19, 0xf5, 0xb9, 0xa6, 0x4c, 0xbe, 0xdb, 0x5a, 0x02, 0xdd, 0x80, 0x22,
+	0xfb, 0xee, 0xe1, 0x46, 0xb5, 0xf5, 0xb4, 0x69, 0x68, 0x2a, 0x2a, 0x41, 0x9e, 0x91, 0xba, 0x5f,
+	0x1d, 0x55, 0xb1, 0xa1, 0x25, 0xf7, 0xfe, 0xaa, 0x42, 0xba, 0x3d, 0x66, 0x8f, 0xf0, 0x0d, 0x40,
+	0xed, 0x4e, 0xad, 0x5d, 0x37, 0x62, 0xe2, 0x35, 0x28, 0x08, 0x7a, 0xb7, 0x57, 0xc5, 0x3d, 0x4d,
+	0xa1, 0x82, 0xc3, 0x99, 0x9d, 0x7a, 0xb5, 0x67, 0x68, 0x09, 0x2a, 0x58, 0x90, 0xea, 0xed, 0x96,
+	0xd8, 0x49, 0x10, 0x0e, 0xdb, 0x3f, 0x31, 0xb4, 0x24, 0xba, 0x09, 0x25, 0x41, 0xc0, 0xc6, 0x97,
+	0x46, 0xad, 0x67, 0xd4, 0xb5, 0x94, 0xb4, 0x67, 0xd7, 0xc0, 0x0d, 0xa3, 0xcb, 0x57, 0xa7, 0xa5,
+	0x1d, 0xb0, 0xd1, 0x6d, 0x3c, 0x6d, 0x69, 0x19, 0xb4, 0x0e, 0x37, 0xc2, 0x1d, 0x70, 0xf5, 0x99,
+	0xd9, 0x3e, 0x38, 0x30, 0xb0, 0x96, 0x45, 0x3a, 0x94, 0x65, 0x32, 0x36, 0xba, 0x9d, 0x76, 0xab,
+	0x6b, 0x68, 0x39, 0x74, 0x1b, 0xd6, 0x27, 0x32, 0x0e, 0xab, 0xbd, 0xda, 0x17, 0x66, 0xb5, 0x56,
+	0x33, 0x3a, 0x3d, 0x0d, 0xd0, 0x26, 0x6c, 0xc4, 0x58, 0x75, 0xa3, 0xd6, 0x6c, 0xb4, 0x0c, 0x2d,
+	0x8f, 0x76, 0xe0, 0x8e, 0xe0, 0xb5, 0x3b, 0x9d, 0x76, 0xcb, 0x68, 0xf5, 0xcc, 0x7a, 0xa3, 0x5b,
+	0x6b, 0xb7, 0x5a, 0x5c, 0xe9, 0x02, 0xba, 0x07, 0x5b, 0xf1, 0x19, 0xd8, 0x98, 0x4e, 0x28, 0x4a,
+	0x3a, 0x35, 0x5a, 0x9d, 0xa3, 0x9e, 0xf9, 0xac, 0x8a, 0x5b, 0x8d, 0xd6, 0x53, 0x6d, 0x4d, 0xe2,
+	0xf0, 0x6d, 0xbb, 0x47, 0x87, 0x87, 0x55, 0xfc, 0x42, 0x2b, 0xa1, 0x5b, 0x70, 0x33, 0xb4, 0xc4,
+	0x8b, 0x56, 0xcd, 0xc4, 0xc6, 0x57, 0x47, 0x46, 0xb7, 0xa7, 0x69, 0x92, 0xdd, 0xba, 0xad, 0x6a,
+	0xa7, 0xfb, 0x45, 0xbb, 0xa7, 0xdd, 0x40, 0x77, 0x40, 0x17, 0xc4, 0x4e, 0xb3, 0xfa, 0xc2, 0xc0,
+	0xa6, 0xd1, 0x6c, 0x1c, 0x36, 0x5a, 0x55, 0xba, 0x3f, 0xda, 0xfb, 0x6d, 0x02, 0x60, 0x1a, 0xde,
+	0xd0, 0x16, 0xdc, 0xa2, 0x66, 0x35, 0xb1, 0x51, 0xed, 0xb6, 0x5b, 0x31, 0xef, 0xea, 0x50, 0x96,
+	0x99, 0xcf, 0x1a, 0x2d, 0x93, 0x19, 0x42, 0xa1, 0x46, 0x92, 0x39, 0xfb, 0xed, 0x2a, 0xae, 0x9b,
+	0x07, 0x47, 0xcd, 0xa6, 0x96, 0xa0, 0xda, 0xca, 0xbc, 0x5e, 0xe3, 0xd0, 0x68, 0x1f, 0xf5, 0x34,
+	0x95, 0x3a, 0x54, 0x66, 0x08, 0xef, 0x25, 0xe3, 0x3a, 0x54, 0x9f, 0x62, 0xc3, 0xa8, 0x33, 0x97,
+	0x69, 0x29, 0xb4, 0x0d, 0xb7, 0x65, 0xe6, 0xc4, 0xaa, 0x4d, 0xe3, 0xa0, 0xa7, 0xa5, 0xe3, 0x8a,
+	0x4c, 0xbd, 0xa1, 0x65, 0xe2, 0x72, 0x9b, 0xed, 0x6e, 0xa3, 0xf5, 0x94, 0x9f, 0x20, 0x4b, 0x11,
+	0x10, 0xd9, 0x74, 0xbf, 0xda, 0xa2, 0xe3, 0xba, 0x96, 0xdb, 0xfb, 0xb5, 0x02, 0xc5, 0xc8, 0x7b,
+	0x10, 0xdd, 0x85, 0xcd, 0xfd, 0x36, 0xf5, 0xf5, 0xc1, 0x41, 0xa3, 0x76, 0xd4, 0xec, 0xbd, 0x88,
+	0x19, 0xea, 0x36, 0xac, 0xc7, 0xf8, 0x98, 0x8a, 0x3b, 0xd4, 0x14, 0xea, 0x8d, 0x18, 0xeb, 0x0b,
+	0xe3, 0x08, 0x37, 0xba, 0xbd, 0x46, 0x4d, 0x4b, 0x50, 0xf5, 0x63, 0xdc, 0x8e, 0x81, 0x0f, 0xa8,
+	0xfa, 0xea, 0xde, 0x6f, 0x14, 0x28, 0xc5, 0x1e, 0x84, 0xe8, 0x3e, 0x6c, 0x1f, 0x34, 0x70, 0xb7,
+	0xc7, 0x2e, 0x8e, 0xd9, 0x69, 0x37, 0x1b, 0xb5, 0xb8, 0x2e, 0x77, 0x40, 0x9f, 0x9d, 0x32, 0x51,
+	0xe7, 0x1e, 0x6c, 0xcd, 0x72, 0xab, 0xcd, 0x9e, 0x81, 0x5b, 0xfc, 0xb2, 0xce, 0xdd, 0xa1, 0xd9,
+	0xee, 0x1a, 0xd8, 0x64, 0x74, 0x4d, 0xdd, 0xfb, 0x46, 0x56, 0x4c, 0xe0, 0x28, 0xba, 0x6c, 0x2e,
+	0x9a, 0xa2, 0x8a, 0x85, 0x20, 0x98, 0xaf, 0x58, 0xe8, 0x15, 0x49, 0xb1, 0x2d, 0xb8, 0x35, 0x3b,
+	0x81, 0x29, 0xa6, 0xa9, 0xfb, 0x4f, 0x7e, 0xfa, 0xf1, 0xa9, 0x1d, 0x9c, 0x5d, 0x1c, 0x3f, 0xec,
+	0xbb, 0xa3, 0x47, 0x67, 0xc4, 0x73, 0xed, 0xfe, 0xd0, 0x3a, 0xf6, 0x1f, 0x39, 0xd6, 0xb9, 0x35,
+	0xb2, 0x3e, 0x1c, 0x7b, 0x2e, 0xad, 0xbf, 0x3e, 0x0c, 0xc8, 0x68, 0x3c, 0xb4, 0x02, 0xf2, 0xc8,
+	0x1a, 0xdb, 0xc7, 0x69, 0xf6, 0x0f, 0xcf, 0x27, 0xff, 0x1e, 0x00, 0x2e, 0x8f, 0x4b, 0x51, 0xee,
+	0x29, 0x00, 0x00,
 }
@@ -17,4 +17,5 @@ require (
 	google.golang.org/api v0.30.0 // indirect
 	google.golang.org/genproto v0.0.0-20201019141844-1ed22bb0c154 // indirect
 	google.golang.org/grpc v1.33.1 // indirect
+	google.golang.org/protobuf v1.25.0
 )
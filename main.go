@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/heroiclabs/nakama-common/api"
@@ -27,11 +28,12 @@ import (
 )
 
 var (
-	errInternalError  = runtime.NewError("internal server error", 13) // INTERNAL
-	errMarshal        = runtime.NewError("cannot marshal type", 13)   // INTERNAL
-	errNoInputAllowed = runtime.NewError("no input allowed", 3)       // INVALID_ARGUMENT
-	errNoUserIdFound  = runtime.NewError("no user ID in context", 3)  // INVALID_ARGUMENT
-	errUnmarshal      = runtime.NewError("cannot unmarshal type", 13) // INTERNAL
+	errBadInput       = runtime.NewError("input contained invalid data", 3) // INVALID_ARGUMENT
+	errInternalError  = runtime.NewError("internal server error", 13)       // INTERNAL
+	errMarshal        = runtime.NewError("cannot marshal type", 13)         // INTERNAL
+	errNoInputAllowed = runtime.NewError("no input allowed", 3)             // INVALID_ARGUMENT
+	errNoUserIdFound  = runtime.NewError("no user ID in context", 3)        // INVALID_ARGUMENT
+	errUnmarshal      = runtime.NewError("cannot unmarshal type", 13)       // INTERNAL
 )
 
 const (
@@ -39,6 +41,18 @@ const (
 	rpcIdRewards   = "rewards"
 	rpcIdFindMatch = "find_match"
 	rpcIdGetMatch  = "get_match"
+
+	rpcIdGetReplay   = "get_replay"
+	rpcIdListReplays = "list_replays"
+
+	rpcIdCreatePrivateMatch = "create_private_match"
+	rpcIdJoinByCode         = "join_by_code"
+
+	rpcIdChallengeFriend  = "challenge_friend"
+	rpcIdAcceptChallenge  = "accept_challenge"
+	rpcIdDeclineChallenge = "decline_challenge"
+
+	rpcIdGetRating = "get_rating"
 )
 
 // func SetSessionVars(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, in *api.AuthenticateCustomRequest) (*api.AuthenticateCustomRequest, error) {
@@ -129,9 +143,13 @@ func beforeLeaderboardWrite(ctx context.Context, logger runtime.Logger, db *sql.
 func InitModule(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, initializer runtime.Initializer) error {
 	initStart := time.Now()
 
-	_, err := firebase.NewApp(context.Background(), nil)
+	// Matches mirror their state to Firestore, if it's available. One client is shared by all of them.
+	var firestoreClient *firestore.Client
+	app, err := firebase.NewApp(context.Background(), nil)
 	if err != nil {
 		logger.Debug("error initializing app: %v\n", err)
+	} else if firestoreClient, err = app.Firestore(context.Background()); err != nil {
+		logger.Error("error initializing firestore: %v", err)
 	}
 
 	logger.Debug("Firebase admin ready")
@@ -183,15 +201,66 @@ func InitModule(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runti
 		return err
 	}
 
+	if err := initializer.RegisterRpc(rpcIdGetReplay, rpcGetReplay(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdListReplays, rpcListReplays(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdCreatePrivateMatch, rpcCreatePrivateMatch(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdJoinByCode, rpcJoinByCode(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdChallengeFriend, rpcChallengeFriend(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdAcceptChallenge, rpcAcceptChallenge(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdDeclineChallenge, rpcDeclineChallenge(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterRpc(rpcIdGetRating, rpcGetRating(marshaler, unmarshaler)); err != nil {
+		return err
+	}
+
 	if err := initializer.RegisterMatch(moduleName, func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule) (runtime.Match, error) {
 		return &MatchHandler{
 			marshaler:   marshaler,
 			unmarshaler: unmarshaler,
+			firestore:   firestoreClient,
+		}, nil
+	}); err != nil {
+		return err
+	}
+
+	if err := initializer.RegisterMatch(ultimateModuleName, func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule) (runtime.Match, error) {
+		return &MatchHandler{
+			marshaler:   marshaler,
+			unmarshaler: unmarshaler,
+			firestore:   firestoreClient,
+			ultimate:    true,
 		}, nil
 	}); err != nil {
 		return err
 	}
 
+	if err := initializer.RegisterMatchmakerMatched(matchmakerMatched); err != nil {
+		return err
+	}
+
+	// Bring back any games interrupted when the server last shut down.
+	restoreMatches(ctx, logger, nk, marshaler, unmarshaler)
+
 	if err := registerSessionEvents(db, nk, initializer); err != nil {
 		return err
 	}
@@ -0,0 +1,225 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	botUserID   = "bot"
+	botUsername = "Bot"
+
+	defaultBotWaitSec = 10
+	botMoveDelaySec   = 1
+
+	// Largest number of free cells the perfect bot will search exhaustively. Beyond this it plays heuristically.
+	botMaxSearchCells = 9
+)
+
+// Compile-time check to make sure the bot can stand in for a real player.
+var _ runtime.MatchData = &botMessage{}
+
+// A server-side player. It occupies a slot in the match presences like any other player, but has no session.
+type botPresence struct{}
+
+func (p *botPresence) GetHidden() bool      { return true }
+func (p *botPresence) GetPersistence() bool { return false }
+func (p *botPresence) GetUsername() string  { return botUsername }
+func (p *botPresence) GetStatus() string    { return "" }
+func (p *botPresence) GetUserId() string    { return botUserID }
+func (p *botPresence) GetSessionId() string { return "" }
+func (p *botPresence) GetNodeId() string    { return "" }
+
+// Input produced by the bot, fed into the match loop alongside client messages.
+type botMessage struct {
+	botPresence
+	opCode      int64
+	data        []byte
+	receiveTime int64
+}
+
+func (m *botMessage) GetOpCode() int64      { return m.opCode }
+func (m *botMessage) GetData() []byte       { return m.data }
+func (m *botMessage) GetReliable() bool     { return true }
+func (m *botMessage) GetReceiveTime() int64 { return m.receiveTime }
+
+// Build the bot's next move as an OPCODE_MOVE message, encoded just like a client would send it.
+func (m *MatchHandler) botMoveMessage(s *MatchState) (runtime.MatchData, error) {
+	var buf bytes.Buffer
+	if err := m.marshaler.Marshal(&buf, &api.Move{Position: s.botPosition()}); err != nil {
+		return nil, err
+	}
+
+	return &botMessage{
+		opCode:      int64(api.OpCode_OPCODE_MOVE),
+		data:        buf.Bytes(),
+		receiveTime: time.Now().UTC().UnixNano() / int64(time.Millisecond),
+	}, nil
+}
+
+// Choose a position for the bot according to its difficulty.
+func (s *MatchState) botPosition() int32 {
+	free := freePositions(s.board)
+	if len(free) == 0 {
+		return -1
+	}
+
+	// The bot only knows strategy for the standard game, otherwise it plays anywhere the rules allow.
+	if s.ultimate || s.variant != standardVariant {
+		positions := s.legalPositions()
+		return positions[s.random.Intn(len(positions))]
+	}
+
+	mark := s.marks[botUserID]
+	switch s.botDifficulty {
+	case api.BotDifficulty_BOT_DIFFICULTY_PERFECT:
+		if len(free) <= botMaxSearchCells {
+			return s.minimaxPosition(mark)
+		}
+		return s.heuristicPosition(mark)
+	case api.BotDifficulty_BOT_DIFFICULTY_HEURISTIC:
+		return s.heuristicPosition(mark)
+	default:
+		return free[s.random.Intn(len(free))]
+	}
+}
+
+// Win if possible, otherwise block the opponent from winning, otherwise pick the cell that extends the most lines
+// still open to the bot. Ties are broken at random so the bot doesn't always play the same game.
+func (s *MatchState) heuristicPosition(mark api.Mark) int32 {
+	opponent := opponentMark(mark)
+	free := freePositions(s.board)
+
+	for _, m := range []api.Mark{mark, opponent} {
+		for _, position := range free {
+			s.board[position] = m
+			wins := findWinningLine(s.board, s.winningPositions, m) != nil
+			s.board[position] = api.Mark_MARK_UNSPECIFIED
+			if wins {
+				return position
+			}
+		}
+	}
+
+	best := make([]int32, 0, len(free))
+	bestScore := -1
+	for _, position := range free {
+		score := 0
+		for _, line := range s.winningPositions {
+			contains := false
+			own := 0
+			blocked := false
+			for _, p := range line {
+				switch s.board[p] {
+				case mark:
+					own++
+				case opponent:
+					blocked = true
+				}
+				if p == position {
+					contains = true
+				}
+			}
+			if contains && !blocked {
+				score += 1 + own*own
+			}
+		}
+
+		if score > bestScore {
+			bestScore = score
+			best = best[:0]
+		}
+		if score == bestScore {
+			best = append(best, position)
+		}
+	}
+
+	return best[s.random.Intn(len(best))]
+}
+
+// Search the remaining game tree and play the best move, preferring faster wins and slower losses.
+func (s *MatchState) minimaxPosition(mark api.Mark) int32 {
+	best := make([]int32, 0)
+	bestScore := -1 << 30
+	for _, position := range freePositions(s.board) {
+		s.board[position] = mark
+		score := -s.negamax(opponentMark(mark), 1, -1<<30, 1<<30)
+		s.board[position] = api.Mark_MARK_UNSPECIFIED
+
+		if score > bestScore {
+			bestScore = score
+			best = best[:0]
+		}
+		if score == bestScore {
+			best = append(best, position)
+		}
+	}
+
+	return best[s.random.Intn(len(best))]
+}
+
+// Score the board from the point of view of the player about to place mark.
+func (s *MatchState) negamax(mark api.Mark, depth, alpha, beta int) int {
+	if findWinningLine(s.board, s.winningPositions, opponentMark(mark)) != nil {
+		// The previous move won the game.
+		return depth - len(s.board) - 1
+	}
+
+	free := freePositions(s.board)
+	if len(free) == 0 {
+		return 0
+	}
+
+	for _, position := range free {
+		s.board[position] = mark
+		score := -s.negamax(opponentMark(mark), depth+1, -beta, -alpha)
+		s.board[position] = api.Mark_MARK_UNSPECIFIED
+
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return alpha
+}
+
+func freePositions(board []api.Mark) []int32 {
+	free := make([]int32, 0, len(board))
+	for position, mark := range board {
+		if mark == api.Mark_MARK_UNSPECIFIED {
+			free = append(free, int32(position))
+		}
+	}
+	return free
+}
+
+func opponentMark(mark api.Mark) api.Mark {
+	switch mark {
+	case api.Mark_MARK_X:
+		return api.Mark_MARK_O
+	case api.Mark_MARK_O:
+		return api.Mark_MARK_X
+	default:
+		return api.Mark_MARK_UNSPECIFIED
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// Pending challenges, owned by the challenged user and keyed by the reserved match ID.
+	challengeCollection = "challenge"
+
+	notificationCodeChallenge         = 201
+	notificationCodeChallengeDeclined = 202
+	notificationCodeChallengeExpired  = 203
+
+	challengeExpirySec = 60
+	// How often a match checks whether its challenge was declined.
+	challengeCheckTicks = tickRate
+
+	friendsListPageSize = 100
+	friendStateMutual   = 0
+)
+
+var (
+	errNotFriends        = runtime.NewError("user is not a friend", 9)           // FAILED_PRECONDITION
+	errChallengeNotFound = runtime.NewError("challenge not found or expired", 5) // NOT_FOUND
+	errChallengeYourself = runtime.NewError("cannot challenge yourself", 3)      // INVALID_ARGUMENT
+)
+
+// Challenge a friend to a match reserved for the two of them. The friend is sent a notification to accept or decline.
+func rpcChallengeFriend(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		userID, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string)
+		if !ok {
+			return "", errNoUserIdFound
+		}
+		username, _ := ctx.Value(runtime.RUNTIME_CTX_USERNAME).(string)
+
+		request := &api.RpcChallengeFriendRequest{}
+		if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+			return "", errUnmarshal
+		}
+		if request.UserId == "" {
+			return "", errBadInput
+		}
+		if request.UserId == userID {
+			return "", errChallengeYourself
+		}
+
+		boardSize, winLength, ok := boardParams(map[string]interface{}{
+			"board_size": nonZeroParam(request.BoardSize),
+			"win_length": nonZeroParam(request.WinLength),
+		}, minSeats)
+		if !ok || !validSeriesLength(int(request.SeriesLength)) {
+			return "", errBadInput
+		}
+		if _, ok := timeControls[request.TimeControl]; request.TimeControl != "" && !ok {
+			return "", errBadInput
+		}
+
+		friend, err := isFriend(ctx, nk, userID, request.UserId)
+		if err != nil {
+			logger.Error("FriendsList error: %v", err)
+			return "", errInternalError
+		}
+		if !friend {
+			return "", errNotFriends
+		}
+
+		matchID, err := nk.MatchCreate(ctx, moduleName, map[string]interface{}{
+			"fast":              request.Fast,
+			"board_size":        boardSize,
+			"win_length":        winLength,
+			"series_length":     int(request.SeriesLength),
+			"time_control":      request.TimeControl,
+			"reserved_user_ids": []string{userID, request.UserId},
+			"challenger_id":     userID,
+		})
+		if err != nil {
+			logger.Error("error creating match: %v", err)
+			return "", errInternalError
+		}
+
+		challenge := &api.Challenge{
+			MatchId:      matchID,
+			ChallengerId: userID,
+			ExpireTime:   time.Now().UTC().Add(challengeExpirySec * time.Second).Unix(),
+		}
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, challenge); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+		if _, err := nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+			Collection:      challengeCollection,
+			Key:             matchID,
+			UserID:          request.UserId,
+			Value:           buf.String(),
+			PermissionRead:  1, // Owner read.
+			PermissionWrite: 0, // No client write.
+		}}); err != nil {
+			logger.Error("StorageWrite error: %v", err)
+			return "", errInternalError
+		}
+
+		if err := nk.NotificationsSend(ctx, []*runtime.NotificationSend{{
+			Code: notificationCodeChallenge,
+			Content: map[string]interface{}{
+				"match_id":      matchID,
+				"fast":          request.Fast,
+				"board_size":    boardSize,
+				"win_length":    winLength,
+				"series_length": request.SeriesLength,
+				"time_control":  request.TimeControl,
+				"expire_time":   challenge.ExpireTime,
+			},
+			Persistent: true,
+			Sender:     userID,
+			Subject:    username + " challenged you to a game!",
+			UserID:     request.UserId,
+		}}); err != nil {
+			logger.Error("NotificationsSend error: %v", err)
+			return "", errInternalError
+		}
+
+		buf.Reset()
+		if err := marshaler.Marshal(&buf, &api.RpcChallengeFriendResponse{
+			MatchId:    matchID,
+			ExpireTime: challenge.ExpireTime,
+		}); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+
+		return buf.String(), nil
+	}
+}
+
+// Accept a challenge, returning the reserved match to join.
+func rpcAcceptChallenge(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		userID, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string)
+		if !ok {
+			return "", errNoUserIdFound
+		}
+
+		challenge, _, err := readChallenge(ctx, logger, nk, unmarshaler, userID, payload)
+		if err != nil {
+			return "", err
+		}
+
+		match, err := nk.MatchGet(ctx, challenge.MatchId)
+		if err != nil {
+			logger.Error("MatchGet error: %v", err)
+			return "", errInternalError
+		}
+		if match == nil {
+			return "", errChallengeNotFound
+		}
+
+		// The challenge itself is cleared by the match once the challenged user joins it.
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, &api.RpcAcceptChallengeResponse{MatchId: challenge.MatchId}); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+
+		return buf.String(), nil
+	}
+}
+
+// Decline a challenge, letting the challenger know.
+func rpcDeclineChallenge(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		userID, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string)
+		if !ok {
+			return "", errNoUserIdFound
+		}
+
+		challenge, version, err := readChallenge(ctx, logger, nk, unmarshaler, userID, payload)
+		if err != nil {
+			return "", err
+		}
+
+		// Removing the challenge tells the match it was declined, so it closes without waiting to expire.
+		if err := nk.StorageDelete(ctx, []*runtime.StorageDelete{{
+			Collection: challengeCollection,
+			Key:        challenge.MatchId,
+			UserID:     userID,
+			Version:    version,
+		}}); err != nil {
+			logger.Error("StorageDelete error: %v", err)
+			return "", errInternalError
+		}
+
+		if err := nk.NotificationsSend(ctx, []*runtime.NotificationSend{{
+			Code: notificationCodeChallengeDeclined,
+			Content: map[string]interface{}{
+				"match_id": challenge.MatchId,
+			},
+			Persistent: true,
+			Sender:     userID,
+			Subject:    "Your challenge was declined.",
+			UserID:     challenge.ChallengerId,
+		}}); err != nil {
+			logger.Error("NotificationsSend error: %v", err)
+			return "", errInternalError
+		}
+
+		return "", nil
+	}
+}
+
+// Read the caller's pending challenge for the match in the request payload. Returns the challenge and its version.
+func readChallenge(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, unmarshaler *jsonpb.Unmarshaler, userID, payload string) (*api.Challenge, string, error) {
+	request := &api.RpcChallengeRequest{}
+	if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+		return nil, "", errUnmarshal
+	}
+	if request.MatchId == "" {
+		return nil, "", errBadInput
+	}
+
+	objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+		Collection: challengeCollection,
+		Key:        request.MatchId,
+		UserID:     userID,
+	}})
+	if err != nil {
+		logger.Error("StorageRead error: %v", err)
+		return nil, "", errInternalError
+	}
+	if len(objects) == 0 {
+		return nil, "", errChallengeNotFound
+	}
+
+	challenge := &api.Challenge{}
+	if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(objects[0].GetValue())), challenge); err != nil {
+		logger.Error("error decoding challenge: %v", err)
+		return nil, "", errUnmarshal
+	}
+	if time.Now().UTC().Unix() > challenge.ExpireTime {
+		return nil, "", errChallengeNotFound
+	}
+
+	return challenge, objects[0].GetVersion(), nil
+}
+
+// Check whether two users are mutual friends.
+func isFriend(ctx context.Context, nk runtime.NakamaModule, userID, friendID string) (bool, error) {
+	state := friendStateMutual
+	cursor := ""
+	for {
+		friends, next, err := nk.FriendsList(ctx, userID, friendsListPageSize, &state, cursor)
+		if err != nil {
+			return false, err
+		}
+		for _, friend := range friends {
+			if friend.GetUser().GetId() == friendID {
+				return true, nil
+			}
+		}
+		if next == "" {
+			return false, nil
+		}
+		cursor = next
+	}
+}
+
+// Settle the challenge the match was created for: answered once the challenged user joins, or expired if they don't
+// join in time. The challenger is only told about expiry if the challenge wasn't already declined.
+func (m *MatchHandler) resolveChallenge(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState, expired bool) {
+	if s.challengedID == "" {
+		return
+	}
+	challengedID := s.challengedID
+	s.challengedID = ""
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+
+	objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+		Collection: challengeCollection,
+		Key:        matchID,
+		UserID:     challengedID,
+	}})
+	if err != nil {
+		logger.Error("error reading challenge: %v", err)
+		return
+	}
+	if len(objects) == 0 {
+		// Already declined.
+		return
+	}
+
+	if err := nk.StorageDelete(ctx, []*runtime.StorageDelete{{
+		Collection: challengeCollection,
+		Key:        matchID,
+		UserID:     challengedID,
+	}}); err != nil {
+		logger.Error("error deleting challenge: %v", err)
+	}
+
+	if expired {
+		if err := nk.NotificationsSend(ctx, []*runtime.NotificationSend{{
+			Code: notificationCodeChallengeExpired,
+			Content: map[string]interface{}{
+				"match_id": matchID,
+			},
+			Persistent: true,
+			Sender:     "", // Server sent.
+			Subject:    "Your challenge expired.",
+			UserID:     s.challengerID,
+		}}); err != nil {
+			logger.Error("error notifying challenger: %v", err)
+		}
+	}
+}
+
+// Check whether the challenge the match was created for was declined. Declining only removes the challenge from
+// storage, so the match looks for it once a second. The challenge is written just after the match is created, so it
+// only counts as declined once it has been seen and then gone missing.
+func (m *MatchHandler) challengeDeclined(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState) bool {
+	if s.challengeRemainingTicks%challengeCheckTicks != 0 {
+		return false
+	}
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+
+	objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+		Collection: challengeCollection,
+		Key:        matchID,
+		UserID:     s.challengedID,
+	}})
+	if err != nil {
+		logger.Error("error reading challenge: %v", err)
+		return false
+	}
+	if len(objects) > 0 {
+		s.challengeSeen = true
+		return false
+	}
+	return s.challengeSeen
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+// Have alice challenge her friend bob. Returns the reserved match, not yet started.
+func challengeTestFriend(t *testing.T, nk *matchtest.NakamaModule) string {
+	t.Helper()
+	nk.AddFriends("alice", "bob")
+	rpc := rpcChallengeFriend(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+	out, err := rpc(userContext("alice"), matchtest.NewLogger(t.Logf), nil, nk, `{"user_id":"bob"}`)
+	if err != nil {
+		t.Fatalf("challenge error: %v", err)
+	}
+	resp := &api.RpcChallengeFriendResponse{}
+	if err := jsonpb.Unmarshal(bytes.NewReader([]byte(out)), resp); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	return resp.MatchId
+}
+
+func TestRpcChallengeFriend(t *testing.T) {
+	rpc := rpcChallengeFriend(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		payload string
+		err     error
+	}{
+		{name: "friend", ctx: userContext("alice"), payload: `{"user_id":"bob"}`},
+		{name: "friend on a larger board", ctx: userContext("alice"), payload: `{"user_id":"bob","board_size":4,"series_length":3}`},
+		{name: "not a friend", ctx: userContext("alice"), payload: `{"user_id":"mallory"}`, err: errNotFriends},
+		{name: "yourself", ctx: userContext("alice"), payload: `{"user_id":"alice"}`, err: errChallengeYourself},
+		{name: "missing user", ctx: userContext("alice"), payload: `{}`, err: errBadInput},
+		{name: "unknown time control", ctx: userContext("alice"), payload: `{"user_id":"bob","time_control":"forever"}`, err: errBadInput},
+		{name: "malformed payload", ctx: userContext("alice"), payload: `{"user_id":`, err: errUnmarshal},
+		{name: "without a user", ctx: context.Background(), payload: `{"user_id":"bob"}`, err: errNoUserIdFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			nk.AddFriends("alice", "bob")
+			out, err := rpc(tt.ctx, matchtest.NewLogger(t.Logf), nil, nk, tt.payload)
+			if err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if len(nk.Created) != 0 || len(nk.Notifications) != 0 {
+					t.Error("match created or friend notified for an invalid challenge")
+				}
+				return
+			}
+
+			resp := &api.RpcChallengeFriendResponse{}
+			if err := jsonpb.Unmarshal(bytes.NewReader([]byte(out)), resp); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if len(nk.Created) != 1 || nk.Created[0].MatchID != resp.MatchId || nk.Created[0].Params["challenger_id"] != "alice" {
+				t.Fatalf("created %v, want one match challenged by alice", nk.Created)
+			}
+			if nk.StorageObject(challengeCollection, resp.MatchId, "bob") == nil {
+				t.Error("challenge not stored for bob")
+			}
+			if len(nk.Notifications) != 1 || nk.Notifications[0].UserID != "bob" || nk.Notifications[0].Code != notificationCodeChallenge {
+				t.Errorf("notifications = %v, want a challenge sent to bob", nk.Notifications)
+			}
+		})
+	}
+}
+
+func TestRpcAcceptChallenge(t *testing.T) {
+	rpc := rpcAcceptChallenge(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+
+	tests := []struct {
+		name   string
+		userID string
+		// Whether the reserved match has already closed.
+		closed bool
+		err    error
+	}{
+		{name: "challenged friend", userID: "bob"},
+		{name: "someone else", userID: "carol", err: errChallengeNotFound},
+		{name: "match closed", userID: "bob", closed: true, err: errChallengeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			matchID := challengeTestFriend(t, nk)
+			if tt.closed {
+				delete(nk.Matches, matchID)
+			}
+
+			out, err := rpc(userContext(tt.userID), matchtest.NewLogger(t.Logf), nil, nk, `{"match_id":"`+matchID+`"}`)
+			if err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			resp := &api.RpcAcceptChallengeResponse{}
+			if err := jsonpb.Unmarshal(bytes.NewReader([]byte(out)), resp); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if resp.MatchId != matchID {
+				t.Errorf("match ID = %v, want %v", resp.MatchId, matchID)
+			}
+		})
+	}
+}
+
+func TestRpcDeclineChallenge(t *testing.T) {
+	rpc := rpcDeclineChallenge(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+
+	tests := []struct {
+		name    string
+		userID  string
+		payload string
+		err     error
+	}{
+		{name: "challenged friend", userID: "bob"},
+		{name: "someone else", userID: "carol", err: errChallengeNotFound},
+		{name: "missing match", userID: "bob", payload: `{}`, err: errBadInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			matchID := challengeTestFriend(t, nk)
+			d := matchtest.NewDriver(newTestHandler(), matchID, nk, matchtest.NewLogger(t.Logf))
+			if !d.Init(nk.Created[0].Params) {
+				t.Fatalf("match init failed with params %v", nk.Created[0].Params)
+			}
+			if ok, reason := d.Join(matchtest.NewPresence("alice"), nil); !ok {
+				t.Fatalf("alice could not join: %v", reason)
+			}
+			d.Steps(tickRate)
+			nk.Notifications = nil
+
+			payload := tt.payload
+			if payload == "" {
+				payload = `{"match_id":"` + matchID + `"}`
+			}
+			if _, err := rpc(userContext(tt.userID), matchtest.NewLogger(t.Logf), nil, nk, payload); err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+
+			// A declined challenge closes its match once the match next checks on it, well before it would expire.
+			closed := !d.Steps(challengeCheckTicks)
+			if closed != (tt.err == nil) {
+				t.Errorf("match closed = %v, want %v", closed, tt.err == nil)
+			}
+			if tt.err != nil {
+				return
+			}
+			if nk.StorageObject(challengeCollection, matchID, "bob") != nil {
+				t.Error("declined challenge still stored")
+			}
+			if len(nk.Notifications) != 1 || nk.Notifications[0].UserID != "alice" || nk.Notifications[0].Code != notificationCodeChallengeDeclined {
+				t.Errorf("notifications = %v, want only the decline sent to alice", nk.Notifications)
+			}
+		})
+	}
+}
+
+func TestMatchChallengeNotYetStored(t *testing.T) {
+	// The match starts before the challenge is stored, which mustn't be mistaken for a decline.
+	d := newTestMatch(t, map[string]interface{}{
+		"reserved_user_ids": []string{"alice", "bob"},
+		"challenger_id":     "alice",
+	})
+	if !d.Steps(challengeCheckTicks * 3) {
+		t.Fatal("match closed before the challenge was stored")
+	}
+}
+
+func TestMatchChallengeJoinDeadline(t *testing.T) {
+	tests := []struct {
+		name    string
+		joining []string
+		// Whether the match should still be open once the challenge would have expired.
+		open bool
+	}{
+		{name: "both joined", joining: []string{"alice", "bob"}, open: true},
+		{name: "challenger never joined", joining: []string{"bob"}, open: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			matchID := challengeTestFriend(t, nk)
+			d := matchtest.NewDriver(newTestHandler(), matchID, nk, matchtest.NewLogger(t.Logf))
+			if !d.Init(nk.Created[0].Params) {
+				t.Fatalf("match init failed with params %v", nk.Created[0].Params)
+			}
+			label := &MatchLabel{}
+			decodeTestLabel(t, d.Label, label)
+			if label.Open != 0 || label.Private != 1 || label.Reserved != 1 {
+				t.Errorf("label = %+v, want a private reserved match", label)
+			}
+			for _, userID := range tt.joining {
+				if ok, reason := d.Join(matchtest.NewPresence(userID), nil); !ok {
+					t.Fatalf("%v could not join: %v", userID, reason)
+				}
+			}
+
+			if !d.Steps(challengeExpirySec*tickRate - 1) {
+				t.Fatal("match closed before the challenge deadline")
+			}
+			if open := d.Step(); open != tt.open {
+				t.Errorf("match open after the challenge deadline = %v, want %v", open, tt.open)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A chess clock: each player starts the game with a time bank, which runs down while it's their turn and is topped up
+// by the increment after every move they make.
+type timeControl struct {
+	bankSec      int
+	incrementSec int
+}
+
+// Time controls players can choose by name. Matches without one use fixed per-turn deadlines instead.
+var timeControls = map[string]timeControl{
+	"bullet": {bankSec: 30, incrementSec: 1},
+	"blitz":  {bankSec: 60, incrementSec: 2},
+	"rapid":  {bankSec: 180, incrementSec: 5},
+}
+
+// Fill each player's time bank at the start of a game.
+func (s *MatchState) startClocks() {
+	s.clocks = nil
+	if s.label.TimeControl == "" {
+		return
+	}
+
+	s.clocks = make(map[string]int64, len(s.marks))
+	for userID := range s.marks {
+		s.clocks[userID] = int64(timeControls[s.label.TimeControl].bankSec * tickRate)
+	}
+}
+
+// The ticks the player about to move has to do so, either what's left in their time bank or the fixed turn time.
+func (s *MatchState) turnTicks() int64 {
+	if s.clocks == nil {
+		return calculateDeadlineTicks(s.label)
+	}
+
+	for userID, mark := range s.marks {
+		if mark == s.mark {
+			return s.clocks[userID]
+		}
+	}
+	return 0
+}
+
+// Stop the clock of a player who just moved, banking the time they had left plus the increment.
+func (s *MatchState) pressClock(userID string) {
+	if s.clocks == nil {
+		return
+	}
+
+	s.clocks[userID] = s.deadlineRemainingTicks + int64(timeControls[s.label.TimeControl].incrementSec*tickRate)
+}
+
+// Each player's remaining time bank in milliseconds, including the time running down for the player to move.
+func (s *MatchState) clockMillis() map[string]int64 {
+	if s.clocks == nil {
+		return nil
+	}
+
+	clocks := make(map[string]int64, len(s.clocks))
+	for userID, ticks := range s.clocks {
+		if s.playing && s.marks[userID] == s.mark {
+			ticks = s.deadlineRemainingTicks
+		}
+		clocks[userID] = ticks * 1000 / tickRate
+	}
+	return clocks
+}
+
+// A search query clause matching only matches with the given time control, or with none.
+func timeControlQuery(name string) string {
+	if name != "" {
+		return fmt.Sprintf("+label.time_control:%v", name)
+	}
+
+	// Labels without a time control have nothing to match on, so exclude all the ones with a time control instead.
+	names := make([]string, 0, len(timeControls))
+	for name := range timeControls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clauses := make([]string, 0, len(names))
+	for _, name := range names {
+		clauses = append(clauses, fmt.Sprintf("-label.time_control:%v", name))
+	}
+	return strings.Join(clauses, " ")
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+// Message encodings clients can ask for with the "encoding" join metadata.
+const (
+	encodingJSON  = "json"
+	encodingProto = "proto"
+)
+
+// How long a session that asked for protobuf has to complete its join, before its request is forgotten.
+const protoJoinTimeoutSec = 10
+
+// Forget the encoding asked for by sessions whose accepted join attempt never turned into a join, such as when the
+// client disconnected in between.
+func (s *MatchState) expireProtoJoins(tick int64) {
+	for sessionID, attemptTick := range s.protoJoinsInProgress {
+		if tick-attemptTick >= protoJoinTimeoutSec*tickRate {
+			delete(s.protoJoinsInProgress, sessionID)
+		}
+	}
+}
+
+// Encode a message and send it to the given presences, or everyone in the match if presences is nil. Each presence gets
+// the message in the encoding it asked for, and the message is encoded at most once per encoding.
+func (m *MatchHandler) broadcast(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, opCode api.OpCode, msg proto.Message, presences []runtime.Presence) {
+	// Most matches only have JSON clients, which a single broadcast covers.
+	if len(s.protoSessions) == 0 {
+		m.send(logger, dispatcher, opCode, msg, presences, false)
+		return
+	}
+
+	if presences == nil {
+		presences = s.recipients()
+	}
+	jsonPresences := make([]runtime.Presence, 0, len(presences))
+	protoPresences := make([]runtime.Presence, 0, len(presences))
+	for _, presence := range presences {
+		if s.protoSessions[presence.GetSessionId()] {
+			protoPresences = append(protoPresences, presence)
+		} else {
+			jsonPresences = append(jsonPresences, presence)
+		}
+	}
+
+	// An empty list of presences would send to everyone, so skip encodings nobody asked for.
+	if len(jsonPresences) > 0 {
+		m.send(logger, dispatcher, opCode, msg, jsonPresences, false)
+	}
+	if len(protoPresences) > 0 {
+		m.send(logger, dispatcher, opCode, msg, protoPresences, true)
+	}
+}
+
+func (m *MatchHandler) send(logger runtime.Logger, dispatcher runtime.MatchDispatcher, opCode api.OpCode, msg proto.Message, presences []runtime.Presence, binary bool) {
+	var data []byte
+	if binary {
+		var err error
+		if data, err = proto.Marshal(msg); err != nil {
+			logger.Error("error encoding message: %v", err)
+			return
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := m.marshaler.Marshal(&buf, msg); err != nil {
+			logger.Error("error encoding message: %v", err)
+			return
+		}
+		data = buf.Bytes()
+	}
+
+	if err := dispatcher.BroadcastMessage(int64(opCode), data, presences, nil, true); err != nil {
+		logger.Error("error broadcasting message: %v", err)
+	}
+}
+
+// Decode a message payload in the encoding its sender asked for.
+func (m *MatchHandler) decode(s *MatchState, message runtime.MatchData, msg proto.Message) error {
+	if s.protoSessions[message.GetSessionId()] {
+		return proto.Unmarshal(message.GetData(), msg)
+	}
+	return m.unmarshaler.Unmarshal(bytes.NewReader(message.GetData()), msg)
+}
+
+// Everyone connected to the match, players and spectators.
+func (s *MatchState) recipients() []runtime.Presence {
+	presences := make([]runtime.Presence, 0, len(s.presences)+len(s.spectators))
+	for userID, presence := range s.presences {
+		if presence != nil && userID != botUserID {
+			presences = append(presences, presence)
+		}
+	}
+	for _, presence := range s.spectators {
+		presences = append(presences, presence)
+	}
+	return presences
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sort"
+
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+// Assign the marks for a new round, giving X and so the first move to the player chosen by the match policy. The
+// other players follow in turn order.
+func (s *MatchState) assignMarks() {
+	// Map iteration order is arbitrary, sort the players so draws depend on the match seed alone.
+	userIDs := make([]string, 0, len(s.presences))
+	for userID := range s.presences {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	first, reason := s.firstMover(userIDs)
+	s.firstMoveReason = reason
+	s.marks = make(map[string]api.Mark, len(userIDs))
+	for i, userID := range userIDs {
+		if userID == first {
+			for j := range userIDs {
+				s.marks[userIDs[(i+j)%len(userIDs)]] = seatMarks[j]
+			}
+		}
+	}
+}
+
+// Choose the player to move first. Policies that depend on the previous round only apply if the same players are
+// playing again, otherwise the first player is drawn at random.
+func (s *MatchState) firstMover(userIDs []string) (string, api.FirstMoveReason) {
+	samePlayers := len(s.marks) == len(userIDs)
+	for _, userID := range userIDs {
+		if _, ok := s.marks[userID]; !ok {
+			samePlayers = false
+		}
+	}
+
+	if samePlayers {
+		switch s.firstMovePolicy {
+		case api.FirstMovePolicy_FIRST_MOVE_POLICY_LOSER_FIRST:
+			if s.winner != api.Mark_MARK_UNSPECIFIED {
+				for _, userID := range userIDs {
+					if s.marks[userID] != s.winner {
+						return userID, api.FirstMoveReason_FIRST_MOVE_REASON_LOSER
+					}
+				}
+			}
+			// Nobody lost, alternate instead.
+			fallthrough
+		case api.FirstMovePolicy_FIRST_MOVE_POLICY_ALTERNATE:
+			// The player who moved second last round moves first.
+			for _, userID := range userIDs {
+				if s.marks[userID] == api.Mark_MARK_O {
+					return userID, api.FirstMoveReason_FIRST_MOVE_REASON_ALTERNATE
+				}
+			}
+		}
+	}
+
+	return userIDs[s.random.Intn(len(userIDs))], api.FirstMoveReason_FIRST_MOVE_REASON_RANDOM
+}
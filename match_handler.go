@@ -15,17 +15,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"math/rand"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
-	firebase "firebase.google.com/go"
 	"github.com/golang/protobuf/jsonpb"
-	"github.com/golang/protobuf/proto"
 
 	"github.com/heroiclabs/nakama-common/rtapi"
 	"github.com/heroiclabs/nakama-common/runtime"
@@ -39,33 +37,49 @@ const (
 
 	maxEmptySec = 30
 
+	defaultMaxSpectators = 10
+
 	delayBetweenGamesSec = 5
 	turnTimeFastSec      = 10
 	turnTimeNormalSec    = 20
-)
 
-var winningPositions = [][]int32{
-	{0, 1, 2},
-	{3, 4, 5},
-	{6, 7, 8},
-	{0, 3, 6},
-	{1, 4, 7},
-	{2, 5, 8},
-	{0, 4, 8},
-	{2, 4, 6},
-}
+	maxSeriesLength = 9
+
+	defaultBoardSize = 3
+	minBoardSize     = 3
+	maxBoardSize     = 15
+	maxWinLength     = 5
+)
 
 // Compile-time check to make sure all required functions are implemented.
 var _ runtime.Match = &MatchHandler{}
 
 type MatchLabel struct {
-	Open int `json:"open"`
-	Fast int `json:"fast"`
+	Open         int    `json:"open"`
+	Fast         int    `json:"fast"`
+	BoardSize    int    `json:"board_size"`
+	WinLength    int    `json:"win_length"`
+	Bot          int    `json:"bot"`
+	Spectators   int    `json:"spectators"`
+	SeriesLength int    `json:"series_length"`
+	TimeControl  string `json:"time_control"`
+	Private      int    `json:"private"`
+	Ultimate     int    `json:"ultimate"`
+	Variant      string `json:"variant"`
+	Seats        int    `json:"seats"`
+	Rating       int    `json:"rating"`
+	Reserved     int    `json:"reserved"`
 }
 
 type MatchHandler struct {
 	marshaler   *jsonpb.Marshaler
 	unmarshaler *jsonpb.Unmarshaler
+
+	// Mirrors match state for clients to follow, nil if Firestore isn't configured.
+	firestore *firestore.Client
+
+	// Plays ultimate tic-tac-toe rather than the classic game.
+	ultimate bool
 }
 
 type MatchState struct {
@@ -76,8 +90,46 @@ type MatchState struct {
 
 	// Currently connected users, or reserved spaces.
 	presences map[string]runtime.Presence
+	// The number of players each game is played with.
+	seats int
 	// Number of users currently in the process of connecting to the match.
 	joinsInProgress int
+	// New players currently in the process of connecting to the match, whose held seats joinsInProgress already counts.
+	playerJoinsInProgress map[string]bool
+
+	// Users watching the match without taking part, keyed by user ID.
+	spectators map[string]runtime.Presence
+	// Users currently in the process of connecting to the match as spectators.
+	spectatorJoinsInProgress map[string]bool
+	// The most spectators allowed to watch at once.
+	maxSpectators int
+
+	// Number of cells along each side of the board.
+	boardSize int
+	// Number of marks in a row needed to win.
+	winLength int
+	// Every line of board positions that wins the game, precomputed from the board size and win length.
+	winningPositions [][]int32
+
+	// The name of the rules the match is played by, and the rules themselves.
+	variant string
+	rules   rules
+
+	// Playing ultimate tic-tac-toe, where the winning lines apply to each sub-board and to the grid of sub-boards.
+	ultimate bool
+	// The player who claimed each sub-board, if any.
+	subBoardWinners []api.Mark
+	// The sub-board the next move must be played in, or anySubBoard.
+	activeSubBoard int32
+
+	// How well the bot plays, if one may be brought in when no other player joins.
+	botDifficulty api.BotDifficulty
+	// Ticks a lone player waits for an opponent before the bot joins.
+	botWaitTicks int64
+	// Ticks the current lone player has left to wait before the bot joins.
+	botWaitRemainingTicks int64
+	// Ticks until the bot makes its move, when it's the bot's turn.
+	botMoveRemainingTicks int64
 
 	// True if there's a game currently in progress.
 	playing bool
@@ -93,24 +145,91 @@ type MatchState struct {
 	winner api.Mark
 	// The winner positions.
 	winnerPositions []int32
+	// Why the current game ended.
+	doneReason api.DoneReason
+	// The user ID of the player with a draw offer pending, if any.
+	drawOfferedBy string
+	// Players out of the current game, by user ID, in matches with more than two seats.
+	eliminated map[string]bool
 	// Ticks until the next game starts, if applicable.
 	nextGameRemainingTicks int64
+
+	// Number of games started in this match so far.
+	gamesPlayed int
+	// The match tick the current game started on.
+	gameStartTick int64
+	// Record of the current game, written to storage when it ends.
+	replay *api.Replay
+
+	// Number of games the series is played over, or 0 to keep playing games indefinitely.
+	seriesLength int
+	// Games won so far in the series, by user ID.
+	seriesScore map[string]int32
+	// The user ID of the player who won the series, once decided.
+	seriesWinner string
+
+	// True while waiting for players to agree to a rematch before the next game.
+	rematchPending bool
+	// Ticks until players who haven't accepted the rematch are treated as declining.
+	rematchRemainingTicks int64
+	// User IDs of players who accepted the rematch.
+	rematchVotes map[string]bool
+
+	// How long players who drop out of a game in progress have to return, zero to leave the turn timer running.
+	reconnectWindowTicks int64
+	// Ticks left for each disconnected player to return before forfeiting the game. The turn timer is paused meanwhile.
+	reconnectRemainingTicks map[string]int64
+
+	// The seed of the random number generator, recorded so random draws can be reviewed.
+	seed int64
+	// How the player who moves first is chosen each round.
+	firstMovePolicy api.FirstMovePolicy
+	// Why the player who moved first in the current or most recent round was chosen.
+	firstMoveReason api.FirstMoveReason
+
+	// The invite code of a private match, released when the match closes.
+	privateCode string
+	// Whether finished games update the ratings of the players.
+	rated bool
+
+	// The only users allowed to join, if the match was created for specific players.
+	reserved map[string]bool
+	// The players of a friend challenge, until the challenged user joins or the challenge expires.
+	challengerID string
+	challengedID string
+	// Ticks until an unanswered challenge expires and the match closes.
+	challengeRemainingTicks int64
+	// Whether the challenge has been seen in storage, so it going missing means it was declined.
+	challengeSeen bool
+	// Ticks until a match reserved for specific players closes, unless all of them have joined.
+	joinRemainingTicks int64
+	// Ticks the players of a match restored between games have left to rejoin it.
+	restoreRemainingTicks int64
+	// The seat reservations as last read by a join attempt, and their storage version, so joining players can claim
+	// their seats without reading them again.
+	seatReservations        *api.SeatReservations
+	seatReservationsVersion string
+
+	// Flood protection state of presences that recently sent too many messages, by session ID.
+	input map[string]*presenceInput
+	// Messages answered with a rejection, and messages dropped for going over the limit, by user ID.
+	rejectedMessages map[string]int64
+	droppedMessages  map[string]int64
+
+	// Sessions that asked for binary protobuf messages rather than JSON, and those still joining by the tick their
+	// join attempt was accepted on.
+	protoSessions        map[string]bool
+	protoJoinsInProgress map[string]int64
+
+	// The sequence number of the most recent state message sent.
+	sequence int64
+
+	// Ticks left in each player's time bank, if the match is played with a chess clock. The bank of the player to move
+	// is only brought up to date once they've moved, deadlineRemainingTicks counts it down meanwhile.
+	clocks map[string]int64
 }
 
 func (m *MatchHandler) MatchInit(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, params map[string]interface{}) (interface{}, int, string) {
-	app, err := firebase.NewApp(context.Background(), nil)
-	if err != nil {
-		logger.Debug("error initializing app: %v\n", err)
-	}
-
-	client, err := app.Firestore(ctx)
-	if err != nil {
-		logger.Error("err", err)
-	}
-
-	defer client.Close()
-	// logger.Debug("Firebase admin ready")
-
 	var debug bool
 	if d, ok := params["debug"]; ok {
 		if dv, ok := d.(bool); ok {
@@ -128,8 +247,164 @@ func (m *MatchHandler) MatchInit(ctx context.Context, logger runtime.Logger, db
 		return nil, 0, ""
 	}
 
+	seats, ok := intParam(params, "seats", minSeats)
+	if !ok || seats < minSeats || seats > maxSeats {
+		logger.Error("invalid match init parameter \"seats\" %v", params["seats"])
+		return nil, 0, ""
+	}
+
+	boardSize, winLength, ok := boardParams(params, seats)
+	if !ok || (m.ultimate && (params["board_size"] != nil || params["win_length"] != nil)) {
+		logger.Error("invalid match init parameters \"board_size\" %v \"win_length\" %v", params["board_size"], params["win_length"])
+		return nil, 0, ""
+	}
+	winningPositions := winningLines(boardSize, winLength)
+	if m.ultimate {
+		// The same lines win each sub-board, and the grid of sub-boards.
+		boardSize, winLength = ultimateBoardSize, ultimateSubBoardSize
+		winningPositions = winningLines(ultimateSubBoardSize, ultimateSubBoardSize)
+	}
+
+	botDifficulty, ok := intParam(params, "bot_difficulty", int(api.BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED))
+	if _, valid := api.BotDifficulty_name[int32(botDifficulty)]; !ok || !valid {
+		logger.Error("invalid match init parameter \"bot_difficulty\" %v", params["bot_difficulty"])
+		return nil, 0, ""
+	}
+	botWaitSec, ok := intParam(params, "bot_wait_sec", defaultBotWaitSec)
+	if !ok || botWaitSec < 0 {
+		logger.Error("invalid match init parameter \"bot_wait_sec\" %v", params["bot_wait_sec"])
+		return nil, 0, ""
+	}
+
+	maxSpectators, ok := intParam(params, "max_spectators", defaultMaxSpectators)
+	if !ok || maxSpectators < 0 {
+		logger.Error("invalid match init parameter \"max_spectators\" %v", params["max_spectators"])
+		return nil, 0, ""
+	}
+
+	seriesLength, ok := intParam(params, "series_length", 0)
+	if !ok || !validSeriesLength(seriesLength) {
+		logger.Error("invalid match init parameter \"series_length\" %v", params["series_length"])
+		return nil, 0, ""
+	}
+
+	reconnectWindowSec, ok := intParam(params, "reconnect_window_sec", defaultReconnectWindowSec)
+	if !ok || reconnectWindowSec < 0 {
+		logger.Error("invalid match init parameter \"reconnect_window_sec\" %v", params["reconnect_window_sec"])
+		return nil, 0, ""
+	}
+
+	firstMovePolicy, ok := intParam(params, "first_move_policy", int(api.FirstMovePolicy_FIRST_MOVE_POLICY_RANDOM))
+	if _, valid := api.FirstMovePolicy_name[int32(firstMovePolicy)]; !ok || !valid {
+		logger.Error("invalid match init parameter \"first_move_policy\" %v", params["first_move_policy"])
+		return nil, 0, ""
+	}
+
+	variant, ok := params["variant"].(string)
+	if params["variant"] == nil || variant == "" {
+		variant = standardVariant
+	}
+	matchRules, valid := variants[variant]
+	if (params["variant"] != nil && !ok) || !valid || (m.ultimate && variant != standardVariant) {
+		logger.Error("invalid match init parameter \"variant\" %v", params["variant"])
+		return nil, 0, ""
+	}
+	if m.ultimate {
+		matchRules = &ultimateRules{}
+	}
+
+	// Only the standard game is played with more than two seats, and only by people.
+	if seats > minSeats && (m.ultimate || variant != standardVariant || botDifficulty != int(api.BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED) || seriesLength != 0) {
+		logger.Error("invalid match init parameter \"seats\" %v for the other parameters", params["seats"])
+		return nil, 0, ""
+	}
+
+	// The rating of the player the match was created for, so others can find a match with an opponent of similar skill.
+	rating, ok := intParam(params, "rating", defaultRating)
+	if !ok || rating < 0 {
+		logger.Error("invalid match init parameter \"rating\" %v", params["rating"])
+		return nil, 0, ""
+	}
+
+	timeControl, ok := params["time_control"].(string)
+	if _, valid := timeControls[timeControl]; (params["time_control"] != nil && !ok) || (timeControl != "" && !valid) {
+		logger.Error("invalid match init parameter \"time_control\" %v", params["time_control"])
+		return nil, 0, ""
+	}
+
+	privateCode, ok := params["private_code"].(string)
+	if params["private_code"] != nil && (!ok || privateCode == "") {
+		logger.Error("invalid match init parameter \"private_code\" %v", params["private_code"])
+		return nil, 0, ""
+	}
+
+	reservedUserIDs, ok := stringsParam(params, "reserved_user_ids")
+	if !ok {
+		logger.Error("invalid match init parameter \"reserved_user_ids\" %v", params["reserved_user_ids"])
+		return nil, 0, ""
+	}
+	reserved := make(map[string]bool, len(reservedUserIDs))
+	for _, userID := range reservedUserIDs {
+		reserved[userID] = true
+	}
+
+	// A challenge is reserved for the challenger and the friend they challenged.
+	challengerID, ok := params["challenger_id"].(string)
+	var challengedID string
+	if params["challenger_id"] != nil {
+		for _, userID := range reservedUserIDs {
+			if userID != challengerID {
+				challengedID = userID
+			}
+		}
+		if !ok || !reserved[challengerID] || len(reserved) != 2 {
+			logger.Error("invalid match init parameter \"challenger_id\" %v", params["challenger_id"])
+			return nil, 0, ""
+		}
+	}
+
+	// Matches recreated after a server restart pick up where they left off.
+	var saved *api.SavedMatch
+	if restore, ok := params["restore"].(string); ok {
+		saved = &api.SavedMatch{}
+		if err := m.unmarshaler.Unmarshal(strings.NewReader(restore), saved); err != nil {
+			logger.Error("invalid match init parameter \"restore\": %v", err)
+			return nil, 0, ""
+		}
+	} else if params["restore"] != nil {
+		logger.Error("invalid match init parameter \"restore\" %v", params["restore"])
+		return nil, 0, ""
+	}
+
+	seed := time.Now().UnixNano()
+	logger.Info("match init with seed %v", seed)
+
 	label := &MatchLabel{
-		Open: 1,
+		Open:         1,
+		BoardSize:    boardSize,
+		WinLength:    winLength,
+		SeriesLength: seriesLength,
+		TimeControl:  timeControl,
+		Variant:      variant,
+		Seats:        seats,
+		Rating:       rating,
+	}
+
+	// Private and reserved matches are never advertised as open, players find them through their invite code or a
+	// reservation instead. Matchmaker matches are reserved but not private, they're public rated games. Only private
+	// matches go unrated, so a restored one stays private even if its invite code couldn't be taken back.
+	if privateCode != "" || len(reserved) > 0 {
+		label.Open = 0
+	}
+	if privateCode != "" || challengerID != "" || (saved != nil && !saved.Rated) {
+		label.Private = 1
+	}
+	if len(reserved) > 0 {
+		label.Reserved = 1
+	}
+
+	if m.ultimate {
+		label.Ultimate = 1
 	}
 
 	if fast {
@@ -137,56 +412,190 @@ func (m *MatchHandler) MatchInit(ctx context.Context, logger runtime.Logger, db
 		logger.Info("match init with Fast param", label.Fast)
 	}
 
+	if _, ok := saved.GetMarks()[botUserID]; ok {
+		label.Bot = 1
+	}
+
 	labelJSON, err := json.Marshal(label)
 	if err != nil {
 		logger.WithField("error", err).Error("match init failed")
 		labelJSON = []byte("{}")
 	}
 
-	_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+	m.updateFirestore(ctx, logger, map[string]interface{}{
 		"playing": false,
 		"debug":   debug,
-		"random":  rand.New(rand.NewSource(time.Now().UnixNano())),
+		"seed":    seed,
 		"label":   label,
 		// "Presences": make(map[string]runtime.Presence, 2),
 		"tickRate": tickRate,
-	}, firestore.MergeAll)
-
-	return &MatchState{
-		debug:     debug,
-		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
-		label:     label,
-		presences: make(map[string]runtime.Presence, 2),
-	}, tickRate, string(labelJSON)
+	})
+
+	s := &MatchState{
+		debug:                 debug,
+		random:                rand.New(rand.NewSource(seed)),
+		label:                 label,
+		presences:             make(map[string]runtime.Presence, seats),
+		seats:                 seats,
+		playerJoinsInProgress: make(map[string]bool),
+
+		spectators:               make(map[string]runtime.Presence),
+		spectatorJoinsInProgress: make(map[string]bool),
+		maxSpectators:            maxSpectators,
+
+		boardSize:        boardSize,
+		winLength:        winLength,
+		winningPositions: winningPositions,
+
+		variant: variant,
+		rules:   matchRules,
+
+		ultimate: m.ultimate,
+
+		botDifficulty:         api.BotDifficulty(botDifficulty),
+		botWaitTicks:          int64(botWaitSec * tickRate),
+		botWaitRemainingTicks: int64(botWaitSec * tickRate),
+
+		seriesLength: seriesLength,
+		seriesScore:  make(map[string]int32, 2),
+
+		reconnectWindowTicks:    int64(reconnectWindowSec * tickRate),
+		reconnectRemainingTicks: make(map[string]int64, 2),
+
+		seed:            seed,
+		firstMovePolicy: api.FirstMovePolicy(firstMovePolicy),
+
+		privateCode: privateCode,
+		// Games between friends by invitation don't count towards ratings.
+		rated: privateCode == "" && challengerID == "",
+
+		reserved:                reserved,
+		challengerID:            challengerID,
+		challengedID:            challengedID,
+		challengeRemainingTicks: challengeExpirySec * tickRate,
+
+		input:            make(map[string]*presenceInput),
+		rejectedMessages: make(map[string]int64),
+		droppedMessages:  make(map[string]int64),
+
+		protoSessions:        make(map[string]bool),
+		protoJoinsInProgress: make(map[string]int64),
+	}
+	// Matches reserved for specific players wait a while for all of them to join: players the matchmaker paired, or
+	// both sides of a challenge for as long as it stands. Restored matches hold seats through the reconnect window
+	// instead.
+	if len(reserved) > 0 && saved == nil {
+		s.joinRemainingTicks = matchmakerJoinTimeoutSec * tickRate
+		if challengerID != "" {
+			s.joinRemainingTicks = challengeExpirySec * tickRate
+		}
+	}
+	if saved != nil {
+		s.restore(saved)
+	}
+
+	return s, tickRate, string(labelJSON)
 }
 
-func (m *MatchHandler) MatchJoinAttempt(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, tick int64, state interface{}, presence runtime.Presence, metadata map[string]string) (interface{}, bool, string) {
+func (m *MatchHandler) MatchJoinAttempt(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, tick int64, state interface{}, presence runtime.Presence, metadata map[string]string) (_ interface{}, accepted bool, _ string) {
 	s := state.(*MatchState)
+	t := time.Now().UTC()
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
 
 	if s.debug {
 		logger.Info("match join attempt username %v user_id %v session_id %v node %v with metadata %v", presence.GetUsername(), presence.GetUserId(), presence.GetSessionId(), presence.GetNodeId(), metadata)
 	}
 
+	// Clients choose the encoding of the messages they send and receive, JSON unless they ask for binary protobuf.
+	switch metadata["encoding"] {
+	case "", encodingJSON:
+	case encodingProto:
+		defer func() {
+			if accepted {
+				s.protoJoinsInProgress[presence.GetSessionId()] = tick
+			}
+		}()
+	default:
+		return s, false, "unsupported encoding"
+	}
+
+	// Matches created for specific players don't let anyone else in.
+	if len(s.reserved) > 0 && !s.reserved[presence.GetUserId()] {
+		return s, false, "not invited"
+	}
+
+	// Spectators are admitted separately from players, up to their own limit.
+	if metadata["role"] == "spectator" {
+		if _, ok := s.presences[presence.GetUserId()]; ok {
+			return s, false, "already playing"
+		}
+		if _, ok := s.spectators[presence.GetUserId()]; ok || s.spectatorJoinsInProgress[presence.GetUserId()] {
+			return s, false, "already joined"
+		}
+		if len(s.spectators)+len(s.spectatorJoinsInProgress) >= s.maxSpectators {
+			return s, false, "spectators full"
+		}
+
+		s.spectatorJoinsInProgress[presence.GetUserId()] = true
+		return s, true, ""
+	}
+
+	// Users watching the match can't also take part in it.
+	if _, ok := s.spectators[presence.GetUserId()]; ok || s.spectatorJoinsInProgress[presence.GetUserId()] {
+		return s, false, "already spectating"
+	}
+
 	// Check if it's a user attempting to rejoin after a disconnect.
 	if presence, ok := s.presences[presence.GetUserId()]; ok {
 		if presence == nil {
 			// User rejoining after a disconnect.
 			s.joinsInProgress++
 			return s, true, ""
+		} else if metadata["use_here"] == "true" {
+			// User moving the game to another device, the current session is kicked once this one has joined.
+			s.joinsInProgress++
+			return s, true, ""
 		} else {
-			// TODO: implement "use here", like whatsapp web
 			// User attempting to join from 2 different devices at the same time.
 			return s, false, "already joined"
 		}
 	}
 
+	// Seats held for players who found the match through a search count as taken, except by the players they're held
+	// for. Joining with a reservation that's no longer held fails, rather than taking someone else's seat. Matches
+	// only found by invitation never have seats held in them.
+	reservations := &api.SeatReservations{}
+	if len(s.reserved) == 0 && s.privateCode == "" {
+		var version string
+		var err error
+		if reservations, version, err = readSeatReservations(ctx, nk, m.unmarshaler, matchID, t); err != nil {
+			logger.Error("error reading seat reservations: %v", err)
+			// Without knowing which seats are held the user could take someone else's, so have them try again.
+			return s, false, "try again"
+		}
+		s.seatReservations, s.seatReservationsVersion = reservations, version
+	}
+	var held int
+	var reserved bool
+	for _, r := range reservations.Reservations {
+		if r.UserId != presence.GetUserId() && !s.playerJoinsInProgress[r.UserId] {
+			held++
+		} else if r.Token == metadata["reservation"] {
+			reserved = true
+		}
+	}
+	if metadata["reservation"] != "" && !reserved {
+		return s, false, "reservation expired"
+	}
+
 	// Check if match is full.
-	if len(s.presences)+s.joinsInProgress >= 2 {
+	if len(s.presences)+s.joinsInProgress+held >= s.seats {
 		return s, false, "match full"
 	}
 
 	// New player attempting to connect.
 	s.joinsInProgress++
+	s.playerJoinsInProgress[presence.GetUserId()] = true
 	return s, true, ""
 }
 
@@ -194,88 +603,66 @@ func (m *MatchHandler) MatchJoin(ctx context.Context, logger runtime.Logger, db
 	s := state.(*MatchState)
 	t := time.Now().UTC()
 
-	app, err := firebase.NewApp(context.Background(), nil)
-	if err != nil {
-		logger.Debug("error initializing app: %v\n", err)
-	}
-
-	client, err := app.Firestore(ctx)
-	if err != nil {
-		logger.Error("err", err)
-	}
-
-	defer client.Close()
-	// logger.Debug("Firebase admin ready")
-
 	if s.debug {
 		for _, presence := range presences {
 			logger.Info("match join username %v user_id %v session_id %v node %v", presence.GetUsername(), presence.GetUserId(), presence.GetSessionId(), presence.GetNodeId())
 		}
 	}
 
+	joined := make(map[string]bool, len(presences))
 	for _, presence := range presences {
-		s.emptyTicks = 0
-		s.presences[presence.GetUserId()] = presence
-		s.joinsInProgress--
-
-		// Check if we must send a message to this user to update them on the current game state.
-		var opCode api.OpCode
-		var msg proto.Message
-		if s.playing {
-			// There's a game still currently in progress, the player is re-joining after a disconnect. Give them a state update.
-			opCode = api.OpCode_OPCODE_UPDATE
-			msg = &api.Update{
-				Board:    s.board,
-				Mark:     s.mark,
-				Marks:    s.marks,
-				Deadline: t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix(),
+		if _, ok := s.protoJoinsInProgress[presence.GetSessionId()]; ok {
+			delete(s.protoJoinsInProgress, presence.GetSessionId())
+			s.protoSessions[presence.GetSessionId()] = true
+		}
+		spectator := s.spectatorJoinsInProgress[presence.GetUserId()]
+		if spectator {
+			delete(s.spectatorJoinsInProgress, presence.GetUserId())
+			s.spectators[presence.GetUserId()] = presence
+		} else {
+			// A player taking over from another device replaces their old session, which keeps their place in the game.
+			if old := s.presences[presence.GetUserId()]; old != nil && old.GetSessionId() != presence.GetSessionId() {
+				if err := dispatcher.MatchKick([]runtime.Presence{old}); err != nil {
+					logger.Error("error kicking replaced session: %v", err)
+				}
 			}
-		} else if s.board != nil && s.marks != nil && s.marks[presence.GetUserId()] > api.Mark_MARK_UNSPECIFIED {
-			// There's no game in progress but we still have a completed game that the user was part of.
-			// They likely disconnected before the game ended, and have since forfeited because they took too long to return.
-			opCode = api.OpCode_OPCODE_DONE
-			msg = &api.Done{
-				Board: s.board,
-				// Mark:            s.mark,
-				Marks:           s.marks,
-				Winner:          s.winner,
-				WinnerPositions: s.winnerPositions,
-				NextGameStart:   t.Add(time.Duration(s.nextGameRemainingTicks/tickRate) * time.Second).Unix(),
+			s.emptyTicks = 0
+			s.presences[presence.GetUserId()] = presence
+			joined[presence.GetUserId()] = true
+			s.joinsInProgress--
+			delete(s.playerJoinsInProgress, presence.GetUserId())
+			m.endReconnectWindow(logger, dispatcher, s, presence.GetUserId(), t)
+			if presence.GetUserId() == s.challengedID {
+				m.resolveChallenge(ctx, logger, nk, s, false)
 			}
 		}
 
-		// Send a message to the user that just joined, if one is needed based on the logic above.
-		if msg != nil {
-			var buf bytes.Buffer
-			if err := m.marshaler.Marshal(&buf, msg); err != nil {
-				logger.Error("error encoding message: %v", err)
-			} else {
-				dispatcher.BroadcastMessage(int64(opCode), buf.Bytes(), []runtime.Presence{presence}, nil, true)
-			}
-		}
+		// Bring the user up to date with the match, whether they're re-joining a game in progress after a disconnect or
+		// from another device, or joining between games.
+		m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_SNAPSHOT, s.snapshot(t), []runtime.Presence{presence})
 	}
 
-	// Check if match was open to new players, but should now be closed.
-	if len(s.presences) >= 2 && s.label.Open != 0 {
-		s.label.Open = 0
-		if labelJSON, err := json.Marshal(s.label); err != nil {
-			logger.Error("error encoding label: %v", err)
-		} else {
-			if err := dispatcher.MatchLabelUpdate(string(labelJSON)); err != nil {
-				logger.Error("error updating label: %v", err)
-			}
+	if len(joined) > 0 {
+		m.claimSeatReservations(ctx, logger, nk, s, joined, t)
+	}
+	if s.joinRemainingTicks > 0 && s.reservedJoined() {
+		s.joinRemainingTicks = 0
+	}
+
+	// Check if match was open to new players, but should now be closed, or is now watched by more spectators.
+	if (len(s.presences) >= s.seats && s.label.Open != 0) || s.label.Spectators != len(s.spectators) {
+		if len(s.presences) >= s.seats {
+			s.label.Open = 0
 		}
+		s.label.Spectators = len(s.spectators)
+		updateLabel(logger, dispatcher, s.label)
 	}
 
 	// Update firestore match label
-	_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+	m.updateFirestore(ctx, logger, map[string]interface{}{
 		"label":     s.label,
 		"presences": s.presences,
-	}, firestore.MergeAll)
-
-	if err != nil {
-		return err
-	}
+	})
 
 	// Update firestore match presences
 	// ? implmenet presences as collection, just for fun and test
@@ -292,6 +679,7 @@ func (m *MatchHandler) MatchJoin(ctx context.Context, logger runtime.Logger, db
 
 func (m *MatchHandler) MatchLeave(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, tick int64, state interface{}, presences []runtime.Presence) interface{} {
 	s := state.(*MatchState)
+	t := time.Now().UTC()
 
 	if s.debug {
 		for _, presence := range presences {
@@ -300,27 +688,38 @@ func (m *MatchHandler) MatchLeave(ctx context.Context, logger runtime.Logger, db
 	}
 
 	for _, presence := range presences {
+		delete(s.protoSessions, presence.GetSessionId())
+		if _, ok := s.spectators[presence.GetUserId()]; ok {
+			delete(s.spectators, presence.GetUserId())
+			continue
+		}
+		// Ignore players who already gave up their seat, such as by declining a rematch, and sessions that were replaced by
+		// the same user joining from another device.
+		current, ok := s.presences[presence.GetUserId()]
+		if !ok || (current != nil && current.GetSessionId() != presence.GetSessionId()) {
+			continue
+		}
 		s.presences[presence.GetUserId()] = nil
+		if s.seats > minSeats {
+			// With more than two seats the game goes on without players who leave.
+			if userID := presence.GetUserId(); s.playing && s.marks[userID] != api.Mark_MARK_UNSPECIFIED && !s.eliminated[userID] {
+				m.forfeit(ctx, logger, nk, dispatcher, s, t, userID, nil, api.DoneReason_DONE_REASON_OPPONENT_LEFT)
+			}
+		} else {
+			m.startReconnectWindow(logger, dispatcher, s, presence.GetUserId(), t)
+		}
 	}
 
-	app, err := firebase.NewApp(context.Background(), nil)
-	if err != nil {
-		logger.Debug("error initializing app: %v\n", err)
-	}
-
-	client, err := app.Firestore(ctx)
-	if err != nil {
-		logger.Error("err", err)
+	if s.label.Spectators != len(s.spectators) {
+		s.label.Spectators = len(s.spectators)
+		updateLabel(logger, dispatcher, s.label)
 	}
 
-	defer client.Close()
-	// logger.Debug("Firebase admin ready")
-
 	// Update firestore match label
-	_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+	m.updateFirestore(ctx, logger, map[string]interface{}{
 		"label":     s.label,
 		"presences": s.presences,
-	}, firestore.MergeAll)
+	})
 
 	// Update firestore match presences
 	// ? implmenet presences as collection, just for fun and test
@@ -337,20 +736,6 @@ func (m *MatchHandler) MatchLeave(ctx context.Context, logger runtime.Logger, db
 func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, tick int64, state interface{}, messages []runtime.MatchData) interface{} {
 	s := state.(*MatchState)
 
-	// TODO: do not initialize admin sdk and fiestore client on every tick
-	app, err := firebase.NewApp(context.Background(), nil)
-	if err != nil {
-		logger.Debug("error initializing app: %v\n", err)
-	}
-
-	client, err := app.Firestore(ctx)
-	if err != nil {
-		logger.Error("err", err)
-	}
-
-	defer client.Close()
-	logger.Debug("Firebase admin ready")
-
 	// matchID := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
 	// logger.Debug(matchID)
 
@@ -359,6 +744,9 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 		logger.Info("match loop match_id %v message count %v", ctx.Value(runtime.RUNTIME_CTX_MATCH_ID), len(messages))
 	}
 
+	s.expireProtoJoins(tick)
+	messages = m.limitInput(logger, dispatcher, s, messages)
+
 	if len(s.presences)+s.joinsInProgress == 0 {
 		s.emptyTicks++
 		if s.emptyTicks >= maxEmptySec*tickRate {
@@ -368,43 +756,125 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 			s.label.Open = 0
 
 			// Update firestore match state
-			_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+			m.updateFirestore(ctx, logger, map[string]interface{}{
 				"label":         s.label,
 				"nextGameStart": nil,
 				"winner":        api.Mark_MARK_UNSPECIFIED,
 				// "Playing":       s.playing,
 				// "Deadline":      nil,
-			}, firestore.MergeAll)
+			})
 
+			m.resolveChallenge(ctx, logger, nk, s, true)
+			m.closeMatch(ctx, logger, nk, dispatcher, s)
+			return nil
+		}
+	}
+
+	// A challenge the challenged user doesn't turn up for closes the match.
+	if s.challengedID != "" {
+		s.challengeRemainingTicks--
+		if s.challengeRemainingTicks <= 0 {
+			logger.Info("closing match after challenge expired")
+			m.resolveChallenge(ctx, logger, nk, s, true)
+			m.closeMatch(ctx, logger, nk, dispatcher, s)
+			return nil
+		}
+		if m.challengeDeclined(ctx, logger, nk, s) {
+			logger.Info("closing match after challenge declined")
+			s.challengedID = ""
+			m.closeMatch(ctx, logger, nk, dispatcher, s)
+			return nil
+		}
+	}
+
+	// Reserved players who don't all turn up close the match.
+	if s.joinRemainingTicks > 0 {
+		s.joinRemainingTicks--
+		if s.joinRemainingTicks == 0 {
+			logger.Info("closing match after reserved players didn't join")
+			m.closeMatch(ctx, logger, nk, dispatcher, s)
 			return nil
 		}
 	}
 
 	t := time.Now().UTC()
 
+	// Clients that think they've fallen out of step get the full state, whatever else is going on.
+	messages = m.answerSyncRequests(logger, dispatcher, s, messages, t)
+
 	// If there's no game in progress check if we can (and should) start one!
 	if !s.playing {
+		// Once the series is decided the match closes, after giving players a moment to see the result.
+		if s.seriesWinner != "" {
+			if s.nextGameRemainingTicks > 0 {
+				s.nextGameRemainingTicks--
+				return s
+			}
+			logger.Info("closing match after series")
+			m.closeMatch(ctx, logger, nk, dispatcher, s)
+			return nil
+		}
+
+		if s.awaitingRestoredPlayers() {
+			return s
+		}
+
 		// Between games any disconnected users are purged, there's no in-progress game for them to return to anyway.
+		departed := make([]string, 0, 1)
 		for userID, presence := range s.presences {
 			if presence == nil {
 				delete(s.presences, userID)
+				departed = append(departed, userID)
 			}
 		}
 
-		// Check if we need to update the label so the match now advertises itself as open to join.
-		if len(s.presences) < 2 && s.label.Open != 1 {
-			s.label.Open = 1
-			if labelJSON, err := json.Marshal(s.label); err != nil {
-				logger.Error("error encoding label: %v", err)
-			} else {
-				if err := dispatcher.MatchLabelUpdate(string(labelJSON)); err != nil {
-					logger.Error("error updating label: %v", err)
+		// A player who walks away from a series part way through concedes it.
+		if len(departed) > 0 && s.seriesLength > 0 && s.gamesPlayed > 0 && len(s.presences) == 1 {
+			for userID := range s.presences {
+				m.endSeries(logger, dispatcher, s, userID)
+			}
+			return s
+		}
+
+		// Players decide whether to play again before the next game. Walking away counts as declining.
+		if s.rematchPending {
+			if len(departed) > 0 {
+				s.rematchPending = false
+				for _, userID := range departed {
+					m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_REMATCH_DECLINE, &api.Rematch{UserId: userID, Sequence: s.nextSequence()}, nil)
 				}
+			} else {
+				m.processRematch(logger, dispatcher, s, messages)
+			}
+		}
+
+		// The bot only stays as long as it has someone to play against.
+		if _, ok := s.presences[botUserID]; ok && len(s.presences) < 2 {
+			delete(s.presences, botUserID)
+			s.label.Bot = 0
+		}
+
+		// Bring in the bot if a lone player has waited long enough for an opponent.
+		if s.botDifficulty != api.BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED && len(s.presences) == 1 && s.joinsInProgress == 0 {
+			s.botWaitRemainingTicks--
+			if s.botWaitRemainingTicks <= 0 {
+				s.presences[botUserID] = &botPresence{}
+				s.label.Open = 0
+				s.label.Bot = 1
+				updateLabel(logger, dispatcher, s.label)
 			}
+		} else if _, ok := s.presences[botUserID]; !ok {
+			s.botWaitRemainingTicks = s.botWaitTicks
+		}
+
+		// Check if we need to update the label so the match now advertises itself as open to join.
+		if len(s.presences) < s.seats && s.label.Open != 1 && s.label.Private == 0 && s.label.Reserved == 0 {
+			s.label.Open = 1
+			updateLabel(logger, dispatcher, s.label)
 		}
 
-		// Check if we have enough players to start a game.
-		if len(s.presences) < 2 {
+		// Check if we have enough players to start a game, and they've agreed to play it.
+		if len(s.presences) < s.seats || s.rematchPending {
 			return s
 		}
 
@@ -416,38 +886,42 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 
 		// We can start a game! Set up the game state and assign the marks to each player.
 		s.playing = true
-		s.board = make([]api.Mark, 9, 9)
-		s.marks = make(map[string]api.Mark, 2)
-		marks := []api.Mark{api.Mark_MARK_X, api.Mark_MARK_O}
-		for userID := range s.presences {
-			s.marks[userID] = marks[0]
-			marks = marks[1:]
+		s.board = make([]api.Mark, s.boardSize*s.boardSize)
+		if s.ultimate {
+			s.startUltimate()
 		}
+		s.assignMarks()
 		s.mark = api.Mark_MARK_X
 		s.winner = api.Mark_MARK_UNSPECIFIED
 		s.winnerPositions = nil
-		s.deadlineRemainingTicks = calculateDeadlineTicks(s.label)
+		s.doneReason = api.DoneReason_DONE_REASON_UNSPECIFIED
+		s.drawOfferedBy = ""
+		s.eliminated = make(map[string]bool, s.seats)
+		s.startClocks()
+		s.deadlineRemainingTicks = s.turnTicks()
 		s.nextGameRemainingTicks = 0
+		s.botMoveRemainingTicks = botMoveDelaySec * tickRate
+		s.startReplay(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string), tick, t)
 
 		// Notify the players a new game has started.
-		var buf bytes.Buffer
-		if err := m.marshaler.Marshal(&buf, &api.Start{
-			Board:    s.board,
-			Marks:    s.marks,
-			Mark:     s.mark,
-			Deadline: t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix(),
-		}); err != nil {
-			logger.Error("error encoding message: %v", err)
-		} else {
-			dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_START), buf.Bytes(), nil, nil, true)
-		}
-
-		if err != nil {
-			logger.Error("Failed adding data to firestore: %v", err)
-		}
+		m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_START, &api.Start{
+			Board:           s.board,
+			Marks:           s.marks,
+			Mark:            s.mark,
+			Deadline:        t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix(),
+			BoardSize:       int32(s.boardSize),
+			WinLength:       int32(s.winLength),
+			SeriesScore:     s.seriesScore,
+			SeriesLength:    int32(s.seriesLength),
+			FirstMoveReason: s.firstMoveReason,
+			Clocks:          s.clockMillis(),
+			Sequence:        s.nextSequence(),
+			Ultimate:        s.ultimateBoard(),
+			Variant:         s.variant,
+		}, nil)
 
 		// Update firestore match state
-		_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+		m.updateFirestore(ctx, logger, map[string]interface{}{
 			"label":         s.label,
 			"playing":       s.playing,
 			"board":         s.board,
@@ -456,120 +930,96 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 			"marks":         s.marks,
 			"nextGameStart": nil,
 			"deadline":      t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix(),
-		}, firestore.MergeAll)
+		})
 
 		return s
 	}
 
+	// The bot takes its turn through the same input handling as everyone else.
+	if s.marks[botUserID] == s.mark {
+		s.botMoveRemainingTicks--
+		if s.botMoveRemainingTicks <= 0 {
+			s.botMoveRemainingTicks = botMoveDelaySec * tickRate
+			if message, err := m.botMoveMessage(s); err != nil {
+				logger.Error("error encoding bot move: %v", err)
+			} else {
+				messages = append(messages, message)
+			}
+		}
+	}
+
 	// There's a game in progress. Check for input, update match state, and send messages to clients.
 	for _, message := range messages {
+		if !s.playing {
+			// The game ended on an earlier message this tick, anything after that is too late.
+			break
+		}
 
 		switch api.OpCode(message.GetOpCode()) {
 		case api.OpCode_OPCODE_MOVE:
+			if _, ok := s.spectators[message.GetUserId()]; ok {
+				// Spectators can only watch.
+				logger.Info("Spectator attempted to move.")
+				m.reject(dispatcher, s, message)
+				continue
+			}
+
 			mark := s.marks[message.GetUserId()]
 			if s.mark != mark {
 				// It is not this player's turn.
 				logger.Info("It is not this player's turn.")
-				dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_REJECTED), nil, []runtime.Presence{message}, nil, true)
+				m.reject(dispatcher, s, message)
 				continue
 			}
 
 			msg := &api.Move{}
-			err := m.unmarshaler.Unmarshal(bytes.NewReader(message.GetData()), msg)
+			err := m.decode(s, message, msg)
 			if err != nil {
 				// Client sent bad data.
 				logger.Info("Client sent bad data.")
-				dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_REJECTED), nil, []runtime.Presence{message}, nil, true)
+				m.reject(dispatcher, s, message)
 				continue
 			}
-			if msg.Position < 0 || msg.Position > 8 || s.board[msg.Position] != api.Mark_MARK_UNSPECIFIED {
-				// Client sent a position outside the board, or one that has already been played.
+			placed, ok := s.rules.placedMark(mark, msg.Mark)
+			if !ok || !s.legalMove(msg.Position) {
+				// Client sent a position outside the board, one that has already been played, or a move the rules don't allow.
 				logger.Info(" Client sent a position outside the board, or one that has already been played.")
-				dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_REJECTED), nil, []runtime.Presence{message}, nil, true)
+				m.reject(dispatcher, s, message)
 				continue
 			}
 
 			// Update the game state.
-			s.board[msg.Position] = mark
-			switch mark {
-			case api.Mark_MARK_X:
-				s.mark = api.Mark_MARK_O
-			case api.Mark_MARK_O:
-				s.mark = api.Mark_MARK_X
-			}
-			s.deadlineRemainingTicks = calculateDeadlineTicks(s.label)
-
-			// Check if game is over through a winning move.
-		winCheck:
-			for _, winningPosition := range winningPositions {
-				for _, position := range winningPosition {
-					if s.board[position] != mark {
-						continue winCheck
-					}
-				}
-
-				// Update state to reflect the winner, and schedule the next game.
-				s.winner = mark
-				s.winnerPositions = winningPosition
-				s.playing = false
-				s.deadlineRemainingTicks = 0
-				s.nextGameRemainingTicks = delayBetweenGamesSec * tickRate
-			}
-			// Check if game is over because no more moves are possible.
-			tie := true
-			for _, mark := range s.board {
-				if mark == api.Mark_MARK_UNSPECIFIED {
-					tie = false
-					break
-				}
-			}
-
-			if tie {
-				// Update state to reflect the tie, and schedule the next game.
-				s.playing = false
-				s.winner = api.Mark_MARK_UNSPECIFIED
-				s.winnerPositions = nil
-				s.deadlineRemainingTicks = 0
-				s.nextGameRemainingTicks = delayBetweenGamesSec * tickRate
+			s.board[msg.Position] = placed
+			s.recordMove(message.GetUserId(), placed, msg.Position, tick)
+			s.drawOfferedBy = ""
+			s.pressClock(message.GetUserId())
+			s.mark = s.nextMark()
+			s.deadlineRemainingTicks = s.turnTicks()
+
+			// Check if game is over through a deciding line, or because no more moves are possible.
+			if winner, winningPosition, reason, over := s.rules.result(s, msg.Position, placed, mark); over {
+				// Update state to reflect the winner or the tie, and schedule the next game.
+				m.endGame(ctx, logger, nk, dispatcher, s, t, winner, winningPosition, reason)
 			}
 
+			// A finished game has already been announced, otherwise let everyone know the game goes on.
 			var deadline = t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix()
-			var nextgamestart = t.Add(time.Duration(s.nextGameRemainingTicks/tickRate) * time.Second).Unix()
-			var opCode api.OpCode
-			var outgoingMsg proto.Message
 			if s.playing {
-				opCode = api.OpCode_OPCODE_UPDATE
-				outgoingMsg = &api.Update{
-					Board:    s.board,
-					Mark:     s.mark,
-					Marks:    s.marks,
-					Deadline: deadline,
-				}
-			} else {
-				opCode = api.OpCode_OPCODE_DONE
-				outgoingMsg = &api.Done{
-					Board: s.board,
-					// Mark:            s.mark,
-					Marks:           s.marks,
-					Winner:          s.winner,
-					WinnerPositions: s.winnerPositions,
-					NextGameStart:   nextgamestart,
-				}
-			}
-
-			var buf bytes.Buffer
-			if err := m.marshaler.Marshal(&buf, outgoingMsg); err != nil {
-				logger.Error("error encoding message: %v", err)
-			} else {
-				dispatcher.BroadcastMessage(int64(opCode), buf.Bytes(), nil, nil, true)
-			}
-
-			if err != nil {
-				logger.Error("Failed adding data to firestore: %v", err)
+				m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_UPDATE, &api.Update{
+					Board:     s.board,
+					Mark:      s.mark,
+					Marks:     s.marks,
+					Deadline:  deadline,
+					BoardSize: int32(s.boardSize),
+					WinLength: int32(s.winLength),
+					Clocks:    s.clockMillis(),
+					Sequence:  s.nextSequence(),
+					Ultimate:  s.ultimateBoard(),
+				}, nil)
 			}
 
 			// Update firestore match state
-			_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+			m.updateFirestore(ctx, logger, map[string]interface{}{
 				"playing":  s.playing,
 				"board":    s.board,
 				"winner":   s.winner,
@@ -577,48 +1027,87 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 				"marks":    s.marks,
 				"deadline": deadline,
 				// "nextGameStart": nextgamestart,
-			}, firestore.MergeAll)
+			})
+
+		case api.OpCode_OPCODE_RESIGN:
+			if s.marks[message.GetUserId()] == api.Mark_MARK_UNSPECIFIED || s.eliminated[message.GetUserId()] {
+				// Only players still in the current round can resign from it.
+				m.reject(dispatcher, s, message)
+				continue
+			}
+
+			m.forfeit(ctx, logger, nk, dispatcher, s, t, message.GetUserId(), nil, api.DoneReason_DONE_REASON_RESIGN)
+
+		case api.OpCode_OPCODE_DRAW_OFFER:
+			if s.marks[message.GetUserId()] == api.Mark_MARK_UNSPECIFIED || s.drawOfferedBy != "" || s.seats > minSeats {
+				// Only players can offer a draw, only one offer may be pending at a time, and only between two players.
+				m.reject(dispatcher, s, message)
+				continue
+			}
+
+			s.drawOfferedBy = message.GetUserId()
+			m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_DRAW_OFFER, &api.DrawOffer{UserId: message.GetUserId(), Sequence: s.nextSequence()}, nil)
+
+			// The bot would rather play on.
+			if _, ok := s.marks[botUserID]; ok {
+				s.drawOfferedBy = ""
+				m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_DRAW_RESPONSE, &api.DrawResponse{UserId: botUserID, Sequence: s.nextSequence()}, nil)
+			}
+
+		case api.OpCode_OPCODE_DRAW_RESPONSE:
+			if s.marks[message.GetUserId()] == api.Mark_MARK_UNSPECIFIED || s.drawOfferedBy == "" || s.drawOfferedBy == message.GetUserId() {
+				// Only the opponent of the player who offered a draw can answer it.
+				m.reject(dispatcher, s, message)
+				continue
+			}
+
+			msg := &api.DrawResponse{}
+			if err := m.decode(s, message, msg); err != nil {
+				// Client sent bad data.
+				m.reject(dispatcher, s, message)
+				continue
+			}
+
+			if msg.Accept {
+				m.endGame(ctx, logger, nk, dispatcher, s, t, api.Mark_MARK_UNSPECIFIED, nil, api.DoneReason_DONE_REASON_AGREED_DRAW)
+			} else {
+				s.drawOfferedBy = ""
+				m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_DRAW_RESPONSE, &api.DrawResponse{UserId: message.GetUserId(), Sequence: s.nextSequence()}, nil)
+			}
 
 		default:
 			// No other opcodes are expected from the client, so automatically treat it as an error.
-			dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_REJECTED), nil, []runtime.Presence{message}, nil, true)
+			m.reject(dispatcher, s, message)
 		}
 	}
 
-	// Keep track of the time remaining for the player to submit their move. Idle players forfeit.
+	// Keep track of the time remaining for the player to submit their move, unless waiting on a player to reconnect.
+	// Idle players forfeit, as do players who don't return in time.
 	if s.playing {
-		s.deadlineRemainingTicks--
-		if s.deadlineRemainingTicks <= 0 {
-			// The player has run out of time to submit their move.
-			s.playing = false
-
-			switch s.mark {
-			case api.Mark_MARK_X:
-				s.winner = api.Mark_MARK_O
-			case api.Mark_MARK_O:
-				s.winner = api.Mark_MARK_X
-			}
-
-			s.winnerPositions = make([]int32, 3)
-			s.deadlineRemainingTicks = 0
-			s.nextGameRemainingTicks = delayBetweenGamesSec * tickRate
-
-			var buf bytes.Buffer
-			if err := m.marshaler.Marshal(&buf, &api.Done{
-				Board: s.board,
-				// Mark:            s.mark,
-				Marks:           s.marks,
-				Winner:          s.winner,
-				WinnerPositions: s.winnerPositions,
-				NextGameStart:   t.Add(time.Duration(s.nextGameRemainingTicks/tickRate) * time.Second).Unix(),
-			}); err != nil {
-				logger.Error("error encoding message: %v", err)
-			} else {
-				dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_DONE), buf.Bytes(), nil, nil, true)
+		if expired := s.expiredReconnectWindows(); len(expired) > 0 {
+			// Players have run out of time to reconnect.
+			m.expireReconnectWindows(ctx, logger, nk, dispatcher, s, t, expired)
+		} else if len(s.reconnectRemainingTicks) == 0 {
+			s.deadlineRemainingTicks--
+			if s.deadlineRemainingTicks <= 0 {
+				// The player has run out of time to submit their move, or their time bank is empty.
+				reason := api.DoneReason_DONE_REASON_TIMEOUT
+				var timedOut string
+				for userID, mark := range s.marks {
+					if mark == s.mark {
+						timedOut = userID
+						if s.presences[userID] == nil {
+							reason = api.DoneReason_DONE_REASON_OPPONENT_LEFT
+						}
+					}
+				}
+				m.forfeit(ctx, logger, nk, dispatcher, s, t, timedOut, make([]int32, 3), reason)
 			}
+		}
 
+		if !s.playing {
 			// Update firestore match state
-			_, err = client.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, map[string]interface{}{
+			m.updateFirestore(ctx, logger, map[string]interface{}{
 				"playing":         s.playing,
 				"winner":          s.winner,
 				"winnerPositions": s.winnerPositions,
@@ -626,8 +1115,7 @@ func (m *MatchHandler) MatchLoop(ctx context.Context, logger runtime.Logger, db
 				"mark":            nil,
 				"deadline":        nil,
 				// "Board":         s.board,
-			}, firestore.MergeAll)
-
+			})
 		}
 	}
 
@@ -639,9 +1127,123 @@ func (m *MatchHandler) MatchTerminate(ctx context.Context, logger runtime.Logger
 		logger.Info("match terminate match_id %v tick %v", ctx.Value(runtime.RUNTIME_CTX_MATCH_ID), tick)
 		logger.Info("match terminate match_id %v grace seconds %v", ctx.Value(runtime.RUNTIME_CTX_MATCH_ID), graceSeconds)
 	}
+
+	s := state.(*MatchState)
+	m.saveMatch(ctx, logger, nk, s, tick)
+	m.closeMatch(ctx, logger, nk, dispatcher, s)
 	return state
 }
 
+// Update state to reflect the end of the current game, record it, schedule the next game, and announce the result.
+func (m *MatchHandler) endGame(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, s *MatchState, t time.Time, winner api.Mark, winnerPositions []int32, reason api.DoneReason) {
+	s.playing = false
+	s.winner = winner
+	s.winnerPositions = winnerPositions
+	s.doneReason = reason
+	s.drawOfferedBy = ""
+	s.deadlineRemainingTicks = 0
+	s.reconnectRemainingTicks = make(map[string]int64, 2)
+	s.nextGameRemainingTicks = delayBetweenGamesSec * tickRate
+
+	// Count the win towards the series, if there is one.
+	var seriesWinner string
+	if s.seriesLength > 0 && winner != api.Mark_MARK_UNSPECIFIED {
+		for userID, mark := range s.marks {
+			if mark != winner {
+				continue
+			}
+			s.seriesScore[userID]++
+			if int(s.seriesScore[userID]) > s.seriesLength/2 {
+				seriesWinner = userID
+			}
+		}
+	}
+
+	m.saveReplay(ctx, logger, nk, s, t)
+	m.updateRatings(ctx, logger, nk, s, t)
+
+	s.seriesWinner = seriesWinner
+	if s.seriesLength == 0 {
+		// Outside of a series the same players only play again if they all want to.
+		s.nextGameRemainingTicks = 0
+		s.startRematch()
+	}
+	done := s.doneMessage(t)
+	done.Sequence = s.nextSequence()
+	m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_DONE, done, nil)
+	if s.seriesWinner != "" {
+		m.endSeries(logger, dispatcher, s, s.seriesWinner)
+	}
+}
+
+// Announce the winner of the series. The match closes once the delay between games has passed.
+func (m *MatchHandler) endSeries(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, winner string) {
+	s.seriesWinner = winner
+	s.nextGameRemainingTicks = delayBetweenGamesSec * tickRate
+
+	m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_SERIES_DONE, &api.SeriesDone{
+		Winner:       winner,
+		SeriesScore:  s.seriesScore,
+		SeriesLength: int32(s.seriesLength),
+		Sequence:     s.nextSequence(),
+	}, nil)
+}
+
+// Build the announcement for the most recently completed game.
+func (s *MatchState) doneMessage(t time.Time) *api.Done {
+	var nextGameStart, rematchDeadline int64
+	if s.rematchPending {
+		rematchDeadline = t.Add(time.Duration(s.rematchRemainingTicks/tickRate) * time.Second).Unix()
+	} else if s.seriesWinner == "" {
+		nextGameStart = t.Add(time.Duration(s.nextGameRemainingTicks/tickRate) * time.Second).Unix()
+	}
+
+	return &api.Done{
+		Board:           s.board,
+		Marks:           s.marks,
+		Winner:          s.winner,
+		WinnerPositions: s.winnerPositions,
+		NextGameStart:   nextGameStart,
+		BoardSize:       int32(s.boardSize),
+		WinLength:       int32(s.winLength),
+		SeriesScore:     s.seriesScore,
+		SeriesLength:    int32(s.seriesLength),
+		Reason:          s.doneReason,
+		RematchDeadline: rematchDeadline,
+		Ultimate:        s.ultimateBoard(),
+	}
+}
+
+// Whether the position may be played next.
+func (s *MatchState) legalMove(position int32) bool {
+	if position < 0 || int(position) >= len(s.board) || s.board[position] != api.Mark_MARK_UNSPECIFIED {
+		return false
+	}
+	return s.rules.allowed(s, position)
+}
+
+// Every position that may be played next.
+func (s *MatchState) legalPositions() []int32 {
+	positions := make([]int32, 0, len(s.board))
+	for _, position := range freePositions(s.board) {
+		if s.rules.allowed(s, position) {
+			positions = append(positions, position)
+		}
+	}
+	return positions
+}
+
+// Mirror match state to Firestore, if it's configured.
+func (m *MatchHandler) updateFirestore(ctx context.Context, logger runtime.Logger, data map[string]interface{}) {
+	if m.firestore == nil {
+		return
+	}
+
+	if _, err := m.firestore.Collection("tictactoe").Doc(ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)).Set(ctx, data, firestore.MergeAll); err != nil {
+		logger.Error("Failed adding data to firestore: %v", err)
+	}
+}
+
 func calculateDeadlineTicks(l *MatchLabel) int64 {
 	if l.Fast == 1 {
 		return turnTimeFastSec * tickRate
@@ -650,6 +1252,134 @@ func calculateDeadlineTicks(l *MatchLabel) int64 {
 	}
 }
 
+// Publish the current label so match listings reflect the latest match state.
+func updateLabel(logger runtime.Logger, dispatcher runtime.MatchDispatcher, label *MatchLabel) {
+	if labelJSON, err := json.Marshal(label); err != nil {
+		logger.Error("error encoding label: %v", err)
+	} else {
+		if err := dispatcher.MatchLabelUpdate(string(labelJSON)); err != nil {
+			logger.Error("error updating label: %v", err)
+		}
+	}
+}
+
+// Read the board dimensions from match init params, falling back to a classic 3x3 board. When only the board size is
+// given the win length defaults to the full side of the board, capped at five in a row.
+func boardParams(params map[string]interface{}, seats int) (int, int, bool) {
+	minSize, defaultSize, defaultMaxWinLength := minBoardSize, defaultBoardSize, maxWinLength
+	if seats > minSeats {
+		minSize, defaultSize, defaultMaxWinLength = multiSeatMinBoardSize, multiSeatDefaultBoardSize, multiSeatDefaultWinLength
+	}
+
+	boardSize, ok := intParam(params, "board_size", defaultSize)
+	if !ok || boardSize < minSize || boardSize > maxBoardSize {
+		return 0, 0, false
+	}
+
+	defaultWinLength := boardSize
+	if defaultWinLength > defaultMaxWinLength {
+		defaultWinLength = defaultMaxWinLength
+	}
+	winLength, ok := intParam(params, "win_length", defaultWinLength)
+	if !ok || winLength < minBoardSize || winLength > boardSize {
+		return 0, 0, false
+	}
+
+	return boardSize, winLength, true
+}
+
+// A series is played over an odd number of games so it can't end level, or 0 to play games indefinitely.
+func validSeriesLength(seriesLength int) bool {
+	return seriesLength == 0 || (seriesLength > 0 && seriesLength <= maxSeriesLength && seriesLength%2 == 1)
+}
+
+// Read a list of strings match init param, empty if not set.
+func stringsParam(params map[string]interface{}, key string) ([]string, bool) {
+	switch v := params[key].(type) {
+	case nil:
+		return []string{}, true
+	case []string:
+		return v, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// Read an integer match init param. Params may arrive as any numeric type depending on how the match was created.
+func intParam(params map[string]interface{}, key string, defaultValue int) (int, bool) {
+	v, ok := params[key]
+	if !ok || v == nil {
+		return defaultValue, true
+	}
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), n == float64(int(n))
+	default:
+		return 0, false
+	}
+}
+
+// Find a line on the board completely filled with the given mark, if any.
+func findWinningLine(board []api.Mark, lines [][]int32, mark api.Mark) []int32 {
+lines:
+	for _, line := range lines {
+		for _, position := range line {
+			if board[position] != mark {
+				continue lines
+			}
+		}
+		return line
+	}
+	return nil
+}
+
+// Compute every row, column and diagonal run of winLength cells on a boardSize x boardSize board.
+func winningLines(boardSize, winLength int) [][]int32 {
+	directions := [][2]int{
+		{0, 1},  // Row.
+		{1, 0},  // Column.
+		{1, 1},  // Diagonal.
+		{1, -1}, // Anti-diagonal.
+	}
+
+	lines := make([][]int32, 0)
+	for _, d := range directions {
+		for row := 0; row < boardSize; row++ {
+			for col := 0; col < boardSize; col++ {
+				endRow := row + d[0]*(winLength-1)
+				endCol := col + d[1]*(winLength-1)
+				if endRow < 0 || endRow >= boardSize || endCol < 0 || endCol >= boardSize {
+					continue
+				}
+
+				line := make([]int32, winLength)
+				for i := 0; i < winLength; i++ {
+					line[i] = int32((row+d[0]*i)*boardSize + col + d[1]*i)
+				}
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return lines
+}
+
 func beforeChannelJoin(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, envelope *rtapi.Envelope) (*rtapi.Envelope, error) {
 	logger.Info("Intercepted request to join channel '%v'", envelope.GetChannelJoin().Target)
 	return envelope, nil
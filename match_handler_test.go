@@ -0,0 +1,695 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+const testMatchID = "match.node"
+
+func newTestHandler() *MatchHandler {
+	return &MatchHandler{
+		marshaler:   &jsonpb.Marshaler{EnumsAsInts: true},
+		unmarshaler: &jsonpb.Unmarshaler{AllowUnknownFields: false},
+	}
+}
+
+// Start a match with the given params, failing the test if it doesn't.
+func newTestMatch(t *testing.T, params map[string]interface{}) *matchtest.Driver {
+	t.Helper()
+	return newTestMatchWithHandler(t, newTestHandler(), params)
+}
+
+func newTestMatchWithHandler(t *testing.T, handler *MatchHandler, params map[string]interface{}) *matchtest.Driver {
+	t.Helper()
+	if _, ok := params["fast"]; !ok {
+		params["fast"] = true
+	}
+	d := matchtest.NewDriver(handler, testMatchID, matchtest.NewNakamaModule(), matchtest.NewLogger(t.Logf))
+	if !d.Init(params) {
+		t.Fatalf("match init failed with params %v", params)
+	}
+	return d
+}
+
+// Join two players and run the match until their first game starts. Returns the players holding X and O.
+func startTestGame(t *testing.T, d *matchtest.Driver) (x, o *matchtest.Presence) {
+	t.Helper()
+	players := startTestGameWith(t, d, "alice", "bob")
+	return players[api.Mark_MARK_X], players[api.Mark_MARK_O]
+}
+
+// Join the users and run the match until their first game starts. Returns the players by the mark they hold.
+func startTestGameWith(t *testing.T, d *matchtest.Driver, userIDs ...string) map[api.Mark]*matchtest.Presence {
+	t.Helper()
+	players := map[string]*matchtest.Presence{}
+	for _, userID := range userIDs {
+		players[userID] = matchtest.NewPresence(userID)
+		if ok, reason := d.Join(players[userID], nil); !ok {
+			t.Fatalf("%v could not join: %v", userID, reason)
+		}
+	}
+
+	if !d.StepUntil(delayBetweenGamesSec*tickRate, func() bool { return d.Dispatcher.Last(int64(api.OpCode_OPCODE_START)) != nil }) {
+		t.Fatal("game did not start")
+	}
+	start := &api.Start{}
+	decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_START)), start)
+	marks := make(map[api.Mark]*matchtest.Presence, len(userIDs))
+	for userID, mark := range start.Marks {
+		marks[mark] = players[userID]
+	}
+	for _, mark := range seatMarks[:len(userIDs)] {
+		if marks[mark] == nil {
+			t.Fatalf("marks not assigned to every player: %v", start.Marks)
+		}
+	}
+	return marks
+}
+
+func decodeTestMessage(t *testing.T, b *matchtest.Broadcast, msg proto.Message) {
+	t.Helper()
+	if b == nil {
+		t.Fatalf("no %T message sent", msg)
+	}
+	if err := jsonpb.Unmarshal(bytes.NewReader(b.Data), msg); err != nil {
+		t.Fatalf("error decoding %T: %v", msg, err)
+	}
+}
+
+func decodeTestLabel(t *testing.T, data string, label *MatchLabel) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(data), label); err != nil {
+		t.Fatalf("error decoding label: %v", err)
+	}
+}
+
+func moveMessage(p *matchtest.Presence, position int32) *matchtest.Message {
+	return matchtest.NewMessage(p, int64(api.OpCode_OPCODE_MOVE), fmt.Sprintf(`{"position":%d}`, position))
+}
+
+func testState(d *matchtest.Driver) *MatchState {
+	return d.State.(*MatchState)
+}
+
+func TestMatchGames(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		// Positions played in turn, starting with X.
+		moves           []int32
+		winner          api.Mark
+		winnerPositions []int32
+		reason          api.DoneReason
+	}{
+		{
+			name:            "X wins a row",
+			params:          map[string]interface{}{},
+			moves:           []int32{0, 3, 1, 4, 2},
+			winner:          api.Mark_MARK_X,
+			winnerPositions: []int32{0, 1, 2},
+			reason:          api.DoneReason_DONE_REASON_WIN_LINE,
+		},
+		{
+			name:            "O wins a column",
+			params:          map[string]interface{}{},
+			moves:           []int32{0, 1, 3, 4, 8, 7},
+			winner:          api.Mark_MARK_O,
+			winnerPositions: []int32{1, 4, 7},
+			reason:          api.DoneReason_DONE_REASON_WIN_LINE,
+		},
+		{
+			name:   "tie on a full board",
+			params: map[string]interface{}{},
+			moves:  []int32{0, 1, 2, 4, 3, 5, 7, 6, 8},
+			winner: api.Mark_MARK_UNSPECIFIED,
+			reason: api.DoneReason_DONE_REASON_BOARD_FULL,
+		},
+		{
+			name:            "X wins a diagonal on a larger board",
+			params:          map[string]interface{}{"board_size": 4, "win_length": 3},
+			moves:           []int32{0, 1, 5, 2, 10},
+			winner:          api.Mark_MARK_X,
+			winnerPositions: []int32{0, 5, 10},
+			reason:          api.DoneReason_DONE_REASON_WIN_LINE,
+		},
+		{
+			name:   "X times out",
+			params: map[string]interface{}{},
+			winner: api.Mark_MARK_O,
+			reason: api.DoneReason_DONE_REASON_TIMEOUT,
+		},
+		{
+			name:   "O times out",
+			params: map[string]interface{}{"fast": false},
+			moves:  []int32{4},
+			winner: api.Mark_MARK_X,
+			reason: api.DoneReason_DONE_REASON_TIMEOUT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, tt.params)
+			x, o := startTestGame(t, d)
+
+			players := []*matchtest.Presence{x, o}
+			for i, position := range tt.moves {
+				if !d.Step(moveMessage(players[i%2], position)) {
+					t.Fatal("match closed during the game")
+				}
+			}
+
+			// Games that aren't decided by the moves end once the player to move runs out of time.
+			done := func() bool { return d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)) != nil }
+			if !d.StepUntil(turnTimeNormalSec*tickRate+1, done) {
+				t.Fatal("game did not end")
+			}
+			if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) > 0 {
+				t.Errorf("%d moves rejected", len(rejected))
+			}
+
+			msg := &api.Done{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)), msg)
+			if msg.Winner != tt.winner {
+				t.Errorf("winner = %v, want %v", msg.Winner, tt.winner)
+			}
+			if msg.Reason != tt.reason {
+				t.Errorf("reason = %v, want %v", msg.Reason, tt.reason)
+			}
+			if tt.winnerPositions != nil && !reflect.DeepEqual(msg.WinnerPositions, tt.winnerPositions) {
+				t.Errorf("winner positions = %v, want %v", msg.WinnerPositions, tt.winnerPositions)
+			}
+			if testState(d).playing {
+				t.Error("game still in progress")
+			}
+		})
+	}
+}
+
+func TestMatchRejectsMoves(t *testing.T) {
+	tests := []struct {
+		name string
+		// Valid moves played first, starting with X.
+		setup []int32
+		// Builds the offending message from the players holding X and O.
+		message func(x, o *matchtest.Presence) *matchtest.Message
+	}{
+		{
+			name:    "out of turn",
+			message: func(x, o *matchtest.Presence) *matchtest.Message { return moveMessage(o, 0) },
+		},
+		{
+			name:    "occupied cell",
+			setup:   []int32{4},
+			message: func(x, o *matchtest.Presence) *matchtest.Message { return moveMessage(o, 4) },
+		},
+		{
+			name:    "outside the board",
+			message: func(x, o *matchtest.Presence) *matchtest.Message { return moveMessage(x, 9) },
+		},
+		{
+			name:    "negative position",
+			message: func(x, o *matchtest.Presence) *matchtest.Message { return moveMessage(x, -1) },
+		},
+		{
+			name: "malformed payload",
+			message: func(x, o *matchtest.Presence) *matchtest.Message {
+				return matchtest.NewMessage(x, int64(api.OpCode_OPCODE_MOVE), `{"position":`)
+			},
+		},
+		{
+			name: "unexpected op code",
+			message: func(x, o *matchtest.Presence) *matchtest.Message {
+				return matchtest.NewMessage(x, int64(api.OpCode_OPCODE_START), `{}`)
+			},
+		},
+		{
+			name: "draw response without an offer",
+			message: func(x, o *matchtest.Presence) *matchtest.Message {
+				return matchtest.NewMessage(o, int64(api.OpCode_OPCODE_DRAW_RESPONSE), `{"accept":true}`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{})
+			x, o := startTestGame(t, d)
+			players := []*matchtest.Presence{x, o}
+			for i, position := range tt.setup {
+				d.Step(moveMessage(players[i%2], position))
+			}
+
+			message := tt.message(x, o)
+			board := append([]api.Mark{}, testState(d).board...)
+			d.Dispatcher.Reset()
+			d.Step(message)
+
+			rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED))
+			if len(rejected) != 1 || !rejected[0].SentTo(message) || len(rejected[0].Presences) != 1 {
+				t.Fatalf("got %d rejections, want 1 sent only to the sender", len(rejected))
+			}
+			if !reflect.DeepEqual(testState(d).board, board) {
+				t.Errorf("board changed to %v", testState(d).board)
+			}
+			if d.Dispatcher.Last(int64(api.OpCode_OPCODE_UPDATE)) != nil {
+				t.Error("update sent for a rejected move")
+			}
+		})
+	}
+}
+
+func TestMatchJoinAttempt(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		joined   []string
+		userID   string
+		metadata map[string]string
+		accepted bool
+		reason   string
+	}{
+		{
+			name:     "first player",
+			params:   map[string]interface{}{},
+			userID:   "alice",
+			accepted: true,
+		},
+		{
+			name:     "second player",
+			params:   map[string]interface{}{},
+			joined:   []string{"alice"},
+			userID:   "bob",
+			accepted: true,
+		},
+		{
+			name:   "match full",
+			params: map[string]interface{}{},
+			joined: []string{"alice", "bob"},
+			userID: "carol",
+			reason: "match full",
+		},
+		{
+			name:   "already joined",
+			params: map[string]interface{}{},
+			joined: []string{"alice"},
+			userID: "alice",
+			reason: "already joined",
+		},
+		{
+			name:     "taking over from another device",
+			params:   map[string]interface{}{},
+			joined:   []string{"alice"},
+			userID:   "alice",
+			metadata: map[string]string{"use_here": "true"},
+			accepted: true,
+		},
+		{
+			name:     "unsupported encoding",
+			params:   map[string]interface{}{},
+			userID:   "alice",
+			metadata: map[string]string{"encoding": "xml"},
+			reason:   "unsupported encoding",
+		},
+		{
+			name:     "spectator in a full match",
+			params:   map[string]interface{}{},
+			joined:   []string{"alice", "bob"},
+			userID:   "carol",
+			metadata: map[string]string{"role": "spectator"},
+			accepted: true,
+		},
+		{
+			name:     "spectators full",
+			params:   map[string]interface{}{"max_spectators": 0},
+			userID:   "carol",
+			metadata: map[string]string{"role": "spectator"},
+			reason:   "spectators full",
+		},
+		{
+			name:     "player spectating",
+			params:   map[string]interface{}{},
+			joined:   []string{"alice"},
+			userID:   "alice",
+			metadata: map[string]string{"role": "spectator"},
+			reason:   "already playing",
+		},
+		{
+			name:   "not invited",
+			params: map[string]interface{}{"reserved_user_ids": []string{"alice", "bob"}},
+			userID: "carol",
+			reason: "not invited",
+		},
+		{
+			name:     "invited",
+			params:   map[string]interface{}{"reserved_user_ids": []string{"alice", "bob"}},
+			userID:   "bob",
+			accepted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, tt.params)
+			for _, userID := range tt.joined {
+				if ok, reason := d.Join(matchtest.NewPresence(userID), nil); !ok {
+					t.Fatalf("%v could not join: %v", userID, reason)
+				}
+			}
+
+			accepted, reason := d.JoinAttempt(matchtest.NewPresence(tt.userID), tt.metadata)
+			if accepted != tt.accepted || reason != tt.reason {
+				t.Errorf("join attempt = (%v, %q), want (%v, %q)", accepted, reason, tt.accepted, tt.reason)
+			}
+		})
+	}
+}
+
+func TestMatchProtoEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		// Whether the accepted join attempt is followed by the join.
+		joins bool
+	}{
+		{name: "session joins", joins: true},
+		{name: "session never joins", joins: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{})
+			alice := matchtest.NewPresence("alice")
+			metadata := map[string]string{"encoding": encodingProto}
+			if tt.joins {
+				if ok, reason := d.Join(alice, metadata); !ok {
+					t.Fatalf("alice could not join: %v", reason)
+				}
+				if snapshot := d.Dispatcher.Last(int64(api.OpCode_OPCODE_SNAPSHOT)); snapshot == nil || json.Valid(snapshot.Data) {
+					t.Error("snapshot not sent as protobuf")
+				}
+			} else if ok, reason := d.JoinAttempt(alice, metadata); !ok {
+				t.Fatalf("alice's join attempt rejected: %v", reason)
+			}
+
+			d.Steps(protoJoinTimeoutSec*tickRate + 1)
+			s := testState(d)
+			if s.protoSessions[alice.SessionID] != tt.joins || len(s.protoJoinsInProgress) != 0 {
+				t.Errorf("proto sessions = %v, joining %v, want the session only if it joined", s.protoSessions, s.protoJoinsInProgress)
+			}
+		})
+	}
+}
+
+func TestMatchRejoin(t *testing.T) {
+	d := newTestMatch(t, map[string]interface{}{"reconnect_window_sec": 5})
+	x, o := startTestGame(t, d)
+	d.Step(moveMessage(x, 4))
+
+	d.Dispatcher.Reset()
+	d.Leave(o)
+	if d.Dispatcher.Last(int64(api.OpCode_OPCODE_OPPONENT_DISCONNECTED)) == nil {
+		t.Fatal("disconnect not announced")
+	}
+
+	// The turn timer is paused while O is away, so waiting longer than a turn doesn't forfeit the game.
+	d.Steps(4 * tickRate)
+	o = o.Reconnect()
+	if ok, reason := d.Join(o, nil); !ok {
+		t.Fatalf("rejoin rejected: %v", reason)
+	}
+	if d.Dispatcher.Last(int64(api.OpCode_OPCODE_OPPONENT_RECONNECTED)) == nil {
+		t.Error("reconnect not announced")
+	}
+	snapshot := &api.Snapshot{}
+	decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_SNAPSHOT)), snapshot)
+	if !snapshot.Playing || snapshot.Mark != api.Mark_MARK_O || snapshot.Board[4] != api.Mark_MARK_X {
+		t.Errorf("rejoining player got snapshot %v", snapshot)
+	}
+
+	// The game carries on from where it was left.
+	d.Step(moveMessage(o, 0))
+	if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) > 0 {
+		t.Fatal("move after rejoining rejected")
+	}
+	if testState(d).board[0] != api.Mark_MARK_O {
+		t.Error("move after rejoining not played")
+	}
+}
+
+func TestMatchReconnectWindowExpires(t *testing.T) {
+	tests := []struct {
+		name string
+		// Leaves the players holding X and O, stepping the match in between if they leave at different times.
+		leave  func(d *matchtest.Driver, x, o *matchtest.Presence)
+		winner api.Mark
+		reason api.DoneReason
+	}{
+		{
+			name:   "one player gone",
+			leave:  func(d *matchtest.Driver, x, o *matchtest.Presence) { d.Leave(x) },
+			winner: api.Mark_MARK_O,
+			reason: api.DoneReason_DONE_REASON_DISCONNECT,
+		},
+		{
+			name: "first to leave forfeits",
+			leave: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				d.Leave(o)
+				d.Step()
+				d.Leave(x)
+			},
+			winner: api.Mark_MARK_X,
+			reason: api.DoneReason_DONE_REASON_DISCONNECT,
+		},
+		{
+			name:   "both gone at once",
+			leave:  func(d *matchtest.Driver, x, o *matchtest.Presence) { d.Leave(x, o) },
+			winner: api.Mark_MARK_UNSPECIFIED,
+			reason: api.DoneReason_DONE_REASON_ABANDONED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{"reconnect_window_sec": 2})
+			x, o := startTestGame(t, d)
+
+			tt.leave(d, x, o)
+			if !d.StepUntil(2*tickRate+1, func() bool { return d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)) != nil }) {
+				t.Fatal("game did not end")
+			}
+			msg := &api.Done{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)), msg)
+			if msg.Winner != tt.winner || msg.Reason != tt.reason {
+				t.Errorf("done = (%v, %v), want (%v, %v)", msg.Winner, msg.Reason, tt.winner, tt.reason)
+			}
+			if len(d.Dispatcher.Sent(int64(api.OpCode_OPCODE_DONE))) != 1 {
+				t.Error("game ended more than once")
+			}
+			if len(testState(d).reconnectRemainingTicks) != 0 {
+				t.Error("reconnect windows still counting down")
+			}
+		})
+	}
+}
+
+func TestMatchSequencesAnnouncements(t *testing.T) {
+	// Play the first game to a win for X.
+	win := func(d *matchtest.Driver, x, o *matchtest.Presence) {
+		for i, position := range []int32{0, 3, 1, 4, 2} {
+			d.Step(moveMessage([]*matchtest.Presence{x, o}[i%2], position))
+		}
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		// Bring about the announcement, once the first game has started.
+		announce func(d *matchtest.Driver, x, o *matchtest.Presence)
+		opCode   api.OpCode
+		msg      interface {
+			proto.Message
+			GetSequence() int64
+		}
+	}{
+		{
+			name:   "draw offer",
+			params: map[string]interface{}{},
+			announce: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				d.Step(matchtest.NewMessage(x, int64(api.OpCode_OPCODE_DRAW_OFFER), `{}`))
+			},
+			opCode: api.OpCode_OPCODE_DRAW_OFFER,
+			msg:    &api.DrawOffer{},
+		},
+		{
+			name:   "draw declined",
+			params: map[string]interface{}{},
+			announce: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				d.Step(matchtest.NewMessage(x, int64(api.OpCode_OPCODE_DRAW_OFFER), `{}`))
+				d.Step(matchtest.NewMessage(o, int64(api.OpCode_OPCODE_DRAW_RESPONSE), `{"accept":false}`))
+			},
+			opCode: api.OpCode_OPCODE_DRAW_RESPONSE,
+			msg:    &api.DrawResponse{},
+		},
+		{
+			name:   "rematch vote",
+			params: map[string]interface{}{},
+			announce: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				win(d, x, o)
+				d.Step(matchtest.NewMessage(x, int64(api.OpCode_OPCODE_REMATCH_ACCEPT), `{}`))
+			},
+			opCode: api.OpCode_OPCODE_REMATCH_ACCEPT,
+			msg:    &api.Rematch{},
+		},
+		{
+			name:   "opponent disconnected",
+			params: map[string]interface{}{"reconnect_window_sec": 5},
+			announce: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				d.Leave(o)
+			},
+			opCode: api.OpCode_OPCODE_OPPONENT_DISCONNECTED,
+			msg:    &api.OpponentDisconnected{},
+		},
+		{
+			name:   "opponent reconnected",
+			params: map[string]interface{}{"reconnect_window_sec": 5},
+			announce: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				d.Leave(o)
+				d.Join(o.Reconnect(), nil)
+			},
+			opCode: api.OpCode_OPCODE_OPPONENT_RECONNECTED,
+			msg:    &api.OpponentReconnected{},
+		},
+		{
+			name:   "series conceded",
+			params: map[string]interface{}{"series_length": 3},
+			announce: func(d *matchtest.Driver, x, o *matchtest.Presence) {
+				win(d, x, o)
+				d.Leave(o)
+				d.Step()
+			},
+			opCode: api.OpCode_OPCODE_SERIES_DONE,
+			msg:    &api.SeriesDone{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, tt.params)
+			x, o := startTestGame(t, d)
+			start := &api.Start{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_START)), start)
+
+			tt.announce(d, x, o)
+			decodeTestMessage(t, d.Dispatcher.Last(int64(tt.opCode)), tt.msg)
+			if tt.msg.GetSequence() <= start.Sequence {
+				t.Errorf("sequence = %v, want after the start's %v", tt.msg.GetSequence(), start.Sequence)
+			}
+		})
+	}
+}
+
+func TestMatchRematchDeclineFreesSeat(t *testing.T) {
+	d := newTestMatch(t, map[string]interface{}{"reconnect_window_sec": 5})
+	x, o := startTestGame(t, d)
+	for i, position := range []int32{0, 3, 1, 4, 2} {
+		d.Step(moveMessage([]*matchtest.Presence{x, o}[i%2], position))
+	}
+
+	// The kicked decliner leaves once the step is over.
+	d.Step(matchtest.NewMessage(o, int64(api.OpCode_OPCODE_REMATCH_DECLINE), `{}`))
+	if len(d.Dispatcher.Kicks) != 1 {
+		t.Fatalf("%d kicks, want the decliner kicked", len(d.Dispatcher.Kicks))
+	}
+	s := testState(d)
+	if _, ok := s.presences[o.UserID]; ok {
+		t.Error("decliner still holds a seat")
+	}
+	if _, ok := s.reconnectRemainingTicks[o.UserID]; ok {
+		t.Error("decliner given a reconnect window")
+	}
+
+	if ok, reason := d.Join(matchtest.NewPresence("carol"), nil); !ok {
+		t.Errorf("new player rejected after a decline: %v", reason)
+	}
+}
+
+func TestMatchIdleClose(t *testing.T) {
+	tests := []struct {
+		name string
+		// Players who join and then leave before the match is left idle.
+		players []string
+	}{
+		{name: "never joined"},
+		{name: "everyone left", players: []string{"alice", "bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{})
+			for _, userID := range tt.players {
+				p := matchtest.NewPresence(userID)
+				d.Join(p, nil)
+				d.Step()
+				d.Leave(p)
+			}
+
+			// Step until the match is empty, and then through the idle period.
+			d.Steps(1)
+			if !d.Steps(maxEmptySec*tickRate - 2) {
+				t.Fatal("match closed early")
+			}
+			if d.Steps(2) {
+				t.Fatal("idle match not closed")
+			}
+			if d.Dispatcher.Last(int64(api.OpCode_OPCODE_MATCH_SUMMARY)) == nil {
+				t.Error("summary not sent on close")
+			}
+		})
+	}
+}
+
+func TestMatchInitRejectsParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+	}{
+		{name: "missing fast", params: map[string]interface{}{}},
+		{name: "board too small", params: map[string]interface{}{"fast": true, "board_size": 2}},
+		{name: "win length longer than board", params: map[string]interface{}{"fast": true, "board_size": 3, "win_length": 4}},
+		{name: "even series", params: map[string]interface{}{"fast": true, "series_length": 2}},
+		{name: "unknown time control", params: map[string]interface{}{"fast": true, "time_control": "glacial"}},
+		{name: "negative rating", params: map[string]interface{}{"fast": true, "rating": -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := matchtest.NewDriver(newTestHandler(), testMatchID, matchtest.NewNakamaModule(), matchtest.NewLogger(nil))
+			if d.Init(tt.params) {
+				t.Error("match init accepted invalid params")
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// The most messages handled from each presence per tick. Anything over is dropped without a reply.
+	maxMessagesPerTick = 4
+
+	// Ticks over the limit, counted as strikes, before a presence is warned and then kicked.
+	inputWarnStrikes = 2
+	inputKickStrikes = 5
+	// Strikes are forgiven once a presence stays within the limit for this long.
+	inputStrikeDecaySec = 10
+)
+
+// Flood protection state for a single presence, by session ID.
+type presenceInput struct {
+	strikes    int
+	quietTicks int64
+}
+
+// Enforce the per-presence message budget on this tick's input. Presences over budget have their excess messages
+// dropped, and earn a strike. Repeat offenders are warned, then kicked, and lose all their messages for the tick.
+func (m *MatchHandler) limitInput(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, messages []runtime.MatchData) []runtime.MatchData {
+	counts := make(map[string]int, len(messages))
+	for _, message := range messages {
+		counts[message.GetSessionId()]++
+	}
+
+	// Forgive presences that have behaved for long enough.
+	for sessionID, input := range s.input {
+		if counts[sessionID] <= maxMessagesPerTick {
+			input.quietTicks++
+			if input.quietTicks >= inputStrikeDecaySec*tickRate {
+				delete(s.input, sessionID)
+			}
+		}
+	}
+
+	kicked := make(map[string]bool)
+	for _, message := range messages {
+		sessionID := message.GetSessionId()
+		if counts[sessionID] <= maxMessagesPerTick {
+			continue
+		}
+		// Only one strike per tick, however far over the limit.
+		counts[sessionID] = maxMessagesPerTick
+
+		input, ok := s.input[sessionID]
+		if !ok {
+			input = &presenceInput{}
+			s.input[sessionID] = input
+		}
+		input.strikes++
+		input.quietTicks = 0
+
+		switch {
+		case input.strikes >= inputKickStrikes:
+			logger.Warn("kicking user %v session %v for flooding the match", message.GetUserId(), sessionID)
+			if err := dispatcher.MatchKick([]runtime.Presence{message}); err != nil {
+				logger.Error("error kicking flooding presence: %v", err)
+			}
+			delete(s.input, sessionID)
+			kicked[sessionID] = true
+		case input.strikes >= inputWarnStrikes:
+			m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_INPUT_WARNING, &api.InputWarning{
+				MaxMessagesPerTick: maxMessagesPerTick,
+				StrikesLeft:        int32(inputKickStrikes - input.strikes),
+			}, []runtime.Presence{message})
+		}
+	}
+
+	accepted := make([]runtime.MatchData, 0, len(messages))
+	handled := make(map[string]int, len(messages))
+	for _, message := range messages {
+		sessionID := message.GetSessionId()
+		handled[sessionID]++
+		if kicked[sessionID] || handled[sessionID] > maxMessagesPerTick {
+			s.droppedMessages[message.GetUserId()]++
+			continue
+		}
+		accepted = append(accepted, message)
+	}
+	return accepted
+}
+
+// Tell a client its message was not accepted.
+func (m *MatchHandler) reject(dispatcher runtime.MatchDispatcher, s *MatchState, message runtime.MatchData) {
+	s.rejectedMessages[message.GetUserId()]++
+	dispatcher.BroadcastMessage(int64(api.OpCode_OPCODE_REJECTED), nil, []runtime.Presence{message}, nil, true)
+}
+
+// Clean up after a match that is about to close, and report on how it went.
+func (m *MatchHandler) closeMatch(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, s *MatchState) {
+	releasePrivateCode(ctx, logger, nk, s)
+	releaseSeatReservations(ctx, logger, nk)
+
+	summary := &api.MatchSummary{
+		GamesPlayed:      int32(s.gamesPlayed),
+		SeriesScore:      s.seriesScore,
+		RejectedMessages: s.rejectedMessages,
+		DroppedMessages:  s.droppedMessages,
+	}
+	logger.Info("match %v summary: %v", ctx.Value(runtime.RUNTIME_CTX_MATCH_ID), summary)
+	m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_MATCH_SUMMARY, summary, nil)
+}
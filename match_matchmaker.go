@@ -0,0 +1,93 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+// How long the players the matchmaker paired have to all join their match, before it closes so those who did join can
+// search again.
+const matchmakerJoinTimeoutSec = 30
+
+// Create an authoritative match for players paired by the server's matchmaker, reserved for just those players.
+//
+// The match settings come from the ticket properties, which clients should also query on so everyone matched asks for
+// the same game. Numeric properties: "fast" (1 for a fast match), "ultimate" (1 for ultimate tic-tac-toe),
+// "board_size" and "win_length". String properties: "variant" and "time_control". Any other properties, such as a
+// skill rating, only matter to the matchmaker query. The number of players matched decides the number of seats.
+func matchmakerMatched(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, entries []runtime.MatchmakerEntry) (string, error) {
+	if len(entries) < minSeats || len(entries) > maxSeats {
+		return "", fmt.Errorf("matchmaker matched %d players, expected %d to %d", len(entries), minSeats, maxSeats)
+	}
+
+	userIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		userIDs = append(userIDs, entry.GetPresence().GetUserId())
+	}
+	properties := entries[0].GetProperties()
+
+	params := map[string]interface{}{
+		"fast":              matchmakerNumber(properties, "fast") == 1,
+		"variant":           matchmakerString(properties, "variant"),
+		"time_control":      matchmakerString(properties, "time_control"),
+		"seats":             len(entries),
+		"reserved_user_ids": userIDs,
+	}
+	module := ultimateModuleName
+	if matchmakerNumber(properties, "ultimate") != 1 {
+		module = moduleName
+		params["board_size"] = nonZeroParam(int32(matchmakerNumber(properties, "board_size")))
+		params["win_length"] = nonZeroParam(int32(matchmakerNumber(properties, "win_length")))
+	}
+
+	matchID, err := nk.MatchCreate(ctx, module, params)
+	if err != nil {
+		logger.Error("error creating matchmaker match: %v", err)
+		return "", err
+	}
+	logger.Info("created matchmaker match %v for %v", matchID, userIDs)
+	return matchID, nil
+}
+
+// A numeric ticket property, or 0 if it's not set.
+func matchmakerNumber(properties map[string]interface{}, key string) int {
+	if v, ok := properties[key].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// A string ticket property, or empty if it's not set.
+func matchmakerString(properties map[string]interface{}, key string) string {
+	if v, ok := properties[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Whether every player the match is reserved for is in it.
+func (s *MatchState) reservedJoined() bool {
+	for userID := range s.reserved {
+		if s.presences[userID] == nil {
+			return false
+		}
+	}
+	return true
+}
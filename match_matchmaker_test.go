@@ -0,0 +1,145 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestMatchmakerMatched(t *testing.T) {
+	tests := []struct {
+		name       string
+		userIDs    []string
+		properties map[string]interface{}
+		module     string
+		// Checks on the label of the created match.
+		label func(l *MatchLabel) bool
+	}{
+		{
+			name:       "two players",
+			userIDs:    []string{"alice", "bob"},
+			properties: map[string]interface{}{"fast": 1.0, "skill": 1500.0},
+			module:     moduleName,
+			label:      func(l *MatchLabel) bool { return l.Fast == 1 && l.Seats == 2 && l.BoardSize == defaultBoardSize },
+		},
+		{
+			name:       "variant on a larger board",
+			userIDs:    []string{"alice", "bob"},
+			properties: map[string]interface{}{"variant": "misere", "board_size": 4.0},
+			module:     moduleName,
+			label:      func(l *MatchLabel) bool { return l.Variant == "misere" && l.BoardSize == 4 && l.WinLength == 4 },
+		},
+		{
+			name:       "ultimate",
+			userIDs:    []string{"alice", "bob"},
+			properties: map[string]interface{}{"ultimate": 1.0, "time_control": "blitz"},
+			module:     ultimateModuleName,
+			label:      func(l *MatchLabel) bool { return l.Ultimate == 1 && l.TimeControl == "blitz" },
+		},
+		{
+			name:    "three players",
+			userIDs: []string{"alice", "bob", "carol"},
+			module:  moduleName,
+			label:   func(l *MatchLabel) bool { return l.Seats == 3 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			entries := make([]runtime.MatchmakerEntry, 0, len(tt.userIDs))
+			for _, userID := range tt.userIDs {
+				entries = append(entries, matchtest.NewMatchmakerEntry(matchtest.NewPresence(userID), tt.properties))
+			}
+
+			matchID, err := matchmakerMatched(context.Background(), matchtest.NewLogger(t.Logf), nil, nk, entries)
+			if err != nil {
+				t.Fatalf("matchmaker matched error: %v", err)
+			}
+			if len(nk.Created) != 1 || nk.Created[0].MatchID != matchID || nk.Created[0].Module != tt.module {
+				t.Fatalf("created %v, want one %v match", nk.Created, tt.module)
+			}
+
+			// Start the match as the server would, and check only the matched players get in.
+			handler := newTestHandler()
+			handler.ultimate = tt.module == ultimateModuleName
+			d := matchtest.NewDriver(handler, matchID, nk, matchtest.NewLogger(t.Logf))
+			if !d.Init(nk.Created[0].Params) {
+				t.Fatalf("match init failed with params %v", nk.Created[0].Params)
+			}
+			label := &MatchLabel{}
+			decodeTestLabel(t, d.Label, label)
+			if !tt.label(label) || label.Open != 0 || label.Private != 0 || label.Reserved != 1 {
+				t.Errorf("label = %+v", label)
+			}
+			if ok, reason := d.JoinAttempt(matchtest.NewPresence("mallory"), nil); ok || reason != "not invited" {
+				t.Errorf("outsider join attempt = (%v, %q), want not invited", ok, reason)
+			}
+			for _, userID := range tt.userIDs {
+				if ok, reason := d.Join(matchtest.NewPresence(userID), nil); !ok {
+					t.Errorf("%v rejected: %v", userID, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchmakerMatchedRejectsTooManyPlayers(t *testing.T) {
+	entries := make([]runtime.MatchmakerEntry, 0, maxSeats+1)
+	for i := 0; i <= maxSeats; i++ {
+		entries = append(entries, matchtest.NewMatchmakerEntry(matchtest.NewPresence(string(rune('a'+i))), nil))
+	}
+	nk := matchtest.NewNakamaModule()
+	if _, err := matchmakerMatched(context.Background(), matchtest.NewLogger(nil), nil, nk, entries); err == nil {
+		t.Error("matchmaker matched accepted too many players")
+	}
+	if len(nk.Created) != 0 {
+		t.Error("match created for too many players")
+	}
+}
+
+func TestMatchmakerJoinTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		joining []string
+		// Whether the match should still be open once the timeout has passed.
+		open bool
+	}{
+		{name: "everyone joined", joining: []string{"alice", "bob"}, open: true},
+		{name: "one player missing", joining: []string{"alice"}, open: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{"reserved_user_ids": []string{"alice", "bob"}})
+			for _, userID := range tt.joining {
+				if ok, reason := d.Join(matchtest.NewPresence(userID), nil); !ok {
+					t.Fatalf("%v rejected: %v", userID, reason)
+				}
+			}
+
+			if !d.Steps(matchmakerJoinTimeoutSec*tickRate - 1) {
+				t.Fatal("match closed before the join timeout")
+			}
+			if open := d.Step(); open != tt.open {
+				t.Errorf("match open after the join timeout = %v, want %v", open, tt.open)
+			}
+		})
+	}
+}
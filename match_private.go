@@ -0,0 +1,253 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// System-owned invite codes of private matches, keyed by the code. Each is deleted when its match closes, or when
+	// it's looked up after expiring or outliving its match.
+	privateCodeCollection = "private_match_code"
+
+	// Codes leave out letters and digits that are easily mistaken for each other when read out.
+	privateCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	privateCodeLength   = 6
+	privateCodeAttempts = 5
+
+	privateCodeExpirySec = 600
+)
+
+var errPrivateCodeNotFound = runtime.NewError("invite code not found or expired", 5) // NOT_FOUND
+
+// Create a match that only players given its invite code can find.
+func rpcCreatePrivateMatch(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		if _, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string); !ok {
+			return "", errNoUserIdFound
+		}
+
+		request := &api.RpcCreatePrivateMatchRequest{}
+		if len(payload) > 0 {
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+				return "", errUnmarshal
+			}
+		}
+
+		boardSize, winLength, ok := boardParams(map[string]interface{}{
+			"board_size": nonZeroParam(request.BoardSize),
+			"win_length": nonZeroParam(request.WinLength),
+		}, minSeats)
+		if !ok || !validSeriesLength(int(request.SeriesLength)) {
+			return "", errBadInput
+		}
+		if _, ok := timeControls[request.TimeControl]; request.TimeControl != "" && !ok {
+			return "", errBadInput
+		}
+
+		// Hold on to a code before creating the match, so the match knows which code to clean up when it closes.
+		code := &api.PrivateMatchCode{
+			ExpireTime: time.Now().UTC().Add(privateCodeExpirySec * time.Second).Unix(),
+		}
+		key, version, err := reservePrivateCode(ctx, logger, nk, marshaler, code)
+		if err != nil {
+			return "", err
+		}
+
+		matchID, err := nk.MatchCreate(ctx, moduleName, map[string]interface{}{
+			"fast":          request.Fast,
+			"board_size":    boardSize,
+			"win_length":    winLength,
+			"series_length": int(request.SeriesLength),
+			"time_control":  request.TimeControl,
+			"private_code":  key,
+		})
+		if err != nil {
+			logger.Error("error creating match: %v", err)
+			deletePrivateCode(ctx, logger, nk, key, version)
+			return "", errInternalError
+		}
+
+		code.MatchId = matchID
+		if _, err := writePrivateCode(ctx, nk, marshaler, key, version, code); err != nil {
+			logger.Error("error writing invite code: %v", err)
+			return "", errInternalError
+		}
+
+		resp := &api.RpcCreatePrivateMatchResponse{
+			MatchId:    matchID,
+			Code:       key,
+			ExpireTime: code.ExpireTime,
+		}
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, resp); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+
+		return buf.String(), nil
+	}
+}
+
+// Look up the private match an invite code belongs to.
+func rpcJoinByCode(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		if _, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string); !ok {
+			return "", errNoUserIdFound
+		}
+
+		request := &api.RpcJoinByCodeRequest{}
+		if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+			return "", errUnmarshal
+		}
+		key := strings.ToUpper(strings.TrimSpace(request.Code))
+		if len(key) != privateCodeLength {
+			return "", errBadInput
+		}
+
+		objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+			Collection: privateCodeCollection,
+			Key:        key,
+		}})
+		if err != nil {
+			logger.Error("StorageRead error: %v", err)
+			return "", errInternalError
+		}
+		if len(objects) == 0 {
+			return "", errPrivateCodeNotFound
+		}
+
+		code := &api.PrivateMatchCode{}
+		if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(objects[0].GetValue())), code); err != nil {
+			logger.Error("error decoding invite code: %v", err)
+			return "", errUnmarshal
+		}
+		// Codes are removed as their match closes, but one that expired or outlived its match, such as when the match
+		// stopped without closing, is removed here instead so it can be handed out again.
+		if time.Now().UTC().Unix() > code.ExpireTime {
+			deletePrivateCode(ctx, logger, nk, key, objects[0].GetVersion())
+			return "", errPrivateCodeNotFound
+		}
+		if code.MatchId == "" {
+			// The match is still being created.
+			return "", errPrivateCodeNotFound
+		}
+
+		match, err := nk.MatchGet(ctx, code.MatchId)
+		if err != nil {
+			logger.Error("MatchGet error: %v", err)
+			return "", errInternalError
+		}
+		if match == nil {
+			deletePrivateCode(ctx, logger, nk, key, objects[0].GetVersion())
+			return "", errPrivateCodeNotFound
+		}
+
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, &api.RpcJoinByCodeResponse{MatchId: code.MatchId}); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+
+		return buf.String(), nil
+	}
+}
+
+// Claim a code no active match is using. Returns the code and the version of its storage object.
+func reservePrivateCode(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, code *api.PrivateMatchCode) (string, string, error) {
+	for i := 0; i < privateCodeAttempts; i++ {
+		key, err := newPrivateCode()
+		if err != nil {
+			logger.Error("error generating invite code: %v", err)
+			return "", "", errInternalError
+		}
+
+		// Writing only if the key does not exist yet keeps codes unique, a taken code just means trying another.
+		version, err := writePrivateCode(ctx, nk, marshaler, key, "*", code)
+		if err != nil {
+			logger.Debug("invite code %v unavailable: %v", key, err)
+			continue
+		}
+		return key, version, nil
+	}
+
+	logger.Error("no invite code available after %v attempts", privateCodeAttempts)
+	return "", "", errInternalError
+}
+
+// Store an invite code, only if the existing object matches the given version. Returns the new version.
+func writePrivateCode(ctx context.Context, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, key, version string, code *api.PrivateMatchCode) (string, error) {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, code); err != nil {
+		return "", err
+	}
+
+	acks, err := nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+		Collection:      privateCodeCollection,
+		Key:             key,
+		Value:           buf.String(),
+		Version:         version,
+		PermissionRead:  0, // No client read.
+		PermissionWrite: 0, // No client write.
+	}})
+	if err != nil {
+		return "", err
+	}
+	return acks[0].GetVersion(), nil
+}
+
+// Delete an invite code, only if the stored object matches the given version. An empty version deletes it regardless.
+func deletePrivateCode(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, key, version string) {
+	if err := nk.StorageDelete(ctx, []*runtime.StorageDelete{{
+		Collection: privateCodeCollection,
+		Key:        key,
+		Version:    version,
+	}}); err != nil {
+		logger.Error("error deleting invite code: %v", err)
+	}
+}
+
+// Release a private match's invite code as the match closes.
+func releasePrivateCode(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState) {
+	if s.privateCode == "" {
+		return
+	}
+
+	deletePrivateCode(ctx, logger, nk, s.privateCode, "")
+	s.privateCode = ""
+}
+
+func newPrivateCode() (string, error) {
+	b := make([]byte, privateCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// The alphabet length divides 256 evenly, so every character is equally likely.
+	for i := range b {
+		b[i] = privateCodeAlphabet[int(b[i])%len(privateCodeAlphabet)]
+	}
+	return string(b), nil
+}
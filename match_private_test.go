@@ -0,0 +1,140 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestRpcCreatePrivateMatch(t *testing.T) {
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	rpc := rpcCreatePrivateMatch(marshaler, unmarshaler)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		payload string
+		err     error
+	}{
+		{name: "default game", ctx: userContext("alice"), payload: ""},
+		{name: "larger board", ctx: userContext("alice"), payload: `{"fast":true,"board_size":4,"series_length":3}`},
+		{name: "board too small", ctx: userContext("alice"), payload: `{"board_size":2}`, err: errBadInput},
+		{name: "even series", ctx: userContext("alice"), payload: `{"series_length":2}`, err: errBadInput},
+		{name: "without a user", ctx: context.Background(), payload: "", err: errNoUserIdFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			out, err := rpc(tt.ctx, matchtest.NewLogger(t.Logf), nil, nk, tt.payload)
+			if err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if len(nk.Created) != 0 {
+					t.Error("match created for an invalid request")
+				}
+				return
+			}
+
+			resp := &api.RpcCreatePrivateMatchResponse{}
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(out)), resp); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if len(resp.Code) != privateCodeLength || strings.Trim(resp.Code, privateCodeAlphabet) != "" {
+				t.Errorf("code = %q", resp.Code)
+			}
+			if len(nk.Created) != 1 || nk.Created[0].MatchID != resp.MatchId || nk.Created[0].Params["private_code"] != resp.Code {
+				t.Fatalf("created %v, want one match with code %v", nk.Created, resp.Code)
+			}
+
+			// The match the code points to hides itself from the match finder.
+			d := matchtest.NewDriver(newTestHandler(), resp.MatchId, nk, matchtest.NewLogger(t.Logf))
+			if !d.Init(nk.Created[0].Params) {
+				t.Fatalf("match init failed with params %v", nk.Created[0].Params)
+			}
+			label := &MatchLabel{}
+			decodeTestLabel(t, d.Label, label)
+			if label.Open != 0 || label.Private != 1 {
+				t.Errorf("label = %+v, want private", label)
+			}
+		})
+	}
+}
+
+func TestRpcJoinByCode(t *testing.T) {
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	rpc := rpcJoinByCode(marshaler, unmarshaler)
+
+	tests := []struct {
+		name string
+		// Sets up the stored code, if any.
+		code    *api.PrivateMatchCode
+		payload string
+		err     error
+		// Whether the stored code should be gone afterwards.
+		deleted bool
+	}{
+		{name: "valid code", code: &api.PrivateMatchCode{MatchId: "match-1.node"}, payload: `{"code":"ABC234"}`},
+		{name: "lower case with spaces", code: &api.PrivateMatchCode{MatchId: "match-1.node"}, payload: `{"code":" abc234 "}`},
+		{name: "unknown code", payload: `{"code":"ABC234"}`, err: errPrivateCodeNotFound},
+		{name: "wrong length", payload: `{"code":"ABC"}`, err: errBadInput},
+		{name: "match still being created", code: &api.PrivateMatchCode{}, payload: `{"code":"ABC234"}`, err: errPrivateCodeNotFound},
+		{name: "expired code", code: &api.PrivateMatchCode{MatchId: "match-1.node", ExpireTime: 1}, payload: `{"code":"ABC234"}`, err: errPrivateCodeNotFound, deleted: true},
+		{name: "match gone", code: &api.PrivateMatchCode{MatchId: "match-9.node"}, payload: `{"code":"ABC234"}`, err: errPrivateCodeNotFound, deleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nk := matchtest.NewNakamaModule()
+			if _, err := nk.MatchCreate(context.Background(), moduleName, nil); err != nil {
+				t.Fatal(err)
+			}
+			if tt.code != nil {
+				if tt.code.ExpireTime == 0 {
+					tt.code.ExpireTime = time.Now().Add(time.Minute).Unix()
+				}
+				if _, err := writePrivateCode(context.Background(), nk, marshaler, "ABC234", "*", tt.code); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			out, err := rpc(userContext("bob"), matchtest.NewLogger(t.Logf), nil, nk, tt.payload)
+			if err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err == nil {
+				resp := &api.RpcJoinByCodeResponse{}
+				if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(out)), resp); err != nil {
+					t.Fatalf("error decoding response: %v", err)
+				}
+				if resp.MatchId != tt.code.MatchId {
+					t.Errorf("match ID = %v, want %v", resp.MatchId, tt.code.MatchId)
+				}
+			}
+			if stored := nk.StorageObject(privateCodeCollection, "ABC234", "") != nil; tt.code != nil && stored == tt.deleted {
+				t.Errorf("code stored = %v, want deleted %v", stored, tt.deleted)
+			}
+		})
+	}
+}
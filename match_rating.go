@@ -0,0 +1,316 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// User-owned ratings, one object per mode of play.
+	ratingCollection = "rating"
+	ratingKeyFast    = "fast"
+	ratingKeyNormal  = "normal"
+
+	// Where new players start, per the Glicko-2 paper.
+	defaultRating     = 1500
+	defaultDeviation  = 350
+	defaultVolatility = 0.06
+	// Constrains how much the volatility can change from one game to the next.
+	ratingTau = 0.5
+	// Converts between the Glicko and Glicko-2 scales.
+	glicko2Scale = 173.7178
+	// Precision of the volatility search.
+	volatilityEpsilon = 0.000001
+
+	// How many of the latest changes are kept with each rating.
+	maxRecentRatingChanges = 10
+	// A conflicting write means a player's rating changed since it was read, so the update is retried from a fresh read.
+	ratingUpdateAttempts = 3
+)
+
+// The outcome of one game against an opponent, as used in a Glicko-2 rating period.
+type glickoResult struct {
+	rating    float64
+	deviation float64
+	// 1 for a win, 0.5 for a tie, 0 for a loss.
+	score float64
+}
+
+// A rating for a player who hasn't played a rated game yet.
+func newRating() *api.Rating {
+	return &api.Rating{
+		Rating:     defaultRating,
+		Deviation:  defaultDeviation,
+		Volatility: defaultVolatility,
+	}
+}
+
+func ratingKey(fast bool) string {
+	if fast {
+		return ratingKeyFast
+	}
+	return ratingKeyNormal
+}
+
+// Rate a player over one rating period by the Glicko-2 system, returning their new rating, deviation and volatility.
+// See http://www.glicko.net/glicko/glicko2.pdf for the steps followed here.
+func glicko2(rating *api.Rating, results []glickoResult) (float64, float64, float64) {
+	mu := (rating.Rating - defaultRating) / glicko2Scale
+	phi := rating.Deviation / glicko2Scale
+	sigma := rating.Volatility
+
+	// A player who didn't play only grows more uncertain.
+	if len(results) == 0 {
+		phi = math.Sqrt(phi*phi + sigma*sigma)
+		return rating.Rating, math.Min(phi*glicko2Scale, defaultDeviation), sigma
+	}
+
+	// Estimated variance of the rating from game outcomes alone, and the estimated improvement.
+	var vInv, improvement float64
+	for _, result := range results {
+		muJ := (result.rating - defaultRating) / glicko2Scale
+		g := glickoG(result.deviation / glicko2Scale)
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		vInv += g * g * e * (1 - e)
+		improvement += g * (result.score - e)
+	}
+	v := 1 / vInv
+	delta := v * improvement
+
+	// Find the new volatility with the Illinois algorithm.
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(ratingTau*ratingTau)
+	}
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*ratingTau) < 0 {
+			k++
+		}
+		B = a - k*ratingTau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > volatilityEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	sigma = math.Exp(A / 2)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+vInv)
+	mu += phi * phi * improvement
+
+	return mu*glicko2Scale + defaultRating, math.Min(phi*glicko2Scale, defaultDeviation), sigma
+}
+
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// Update the ratings of both players from the game that just ended. Games against the bot, games between friends by
+// invitation, games with more than two seats and games both players abandoned are left unrated.
+func (m *MatchHandler) updateRatings(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState, t time.Time) {
+	if !s.rated || s.seats > minSeats || len(s.marks) != minSeats || s.doneReason == api.DoneReason_DONE_REASON_ABANDONED {
+		return
+	}
+	if _, ok := s.marks[botUserID]; ok {
+		return
+	}
+
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	var err error
+	for attempt := 0; attempt < ratingUpdateAttempts; attempt++ {
+		if err = m.writeRatings(ctx, nk, s, matchID, t); err == nil {
+			return
+		}
+	}
+	logger.Error("error updating ratings: %v", err)
+}
+
+// Read both players' ratings, rate the game, and write both new ratings in one update. The update fails without
+// changing either rating if another game changed one of them in the meantime.
+func (m *MatchHandler) writeRatings(ctx context.Context, nk runtime.NakamaModule, s *MatchState, matchID string, t time.Time) error {
+	key := ratingKey(s.label.Fast == 1)
+	userIDs := make([]string, 0, len(s.marks))
+	for userID := range s.marks {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	reads := make([]*runtime.StorageRead, 0, len(userIDs))
+	for _, userID := range userIDs {
+		reads = append(reads, &runtime.StorageRead{
+			Collection: ratingCollection,
+			Key:        key,
+			UserID:     userID,
+		})
+	}
+	objects, err := nk.StorageRead(ctx, reads)
+	if err != nil {
+		return err
+	}
+
+	ratings := make(map[string]*api.Rating, len(userIDs))
+	// Players without a rating yet are only written if no other game creates their rating first.
+	versions := make(map[string]string, len(userIDs))
+	for _, userID := range userIDs {
+		ratings[userID] = newRating()
+		versions[userID] = "*"
+	}
+	for _, object := range objects {
+		rating := &api.Rating{}
+		if err := m.unmarshaler.Unmarshal(bytes.NewReader([]byte(object.GetValue())), rating); err != nil {
+			return err
+		}
+		ratings[object.GetUserId()] = rating
+		versions[object.GetUserId()] = object.GetVersion()
+	}
+
+	writes := make([]*runtime.StorageWrite, 0, len(userIDs))
+	for i, userID := range userIDs {
+		opponentID := userIDs[1-i]
+		rating, opponent := ratings[userID], ratings[opponentID]
+
+		score := 0.5
+		switch s.winner {
+		case api.Mark_MARK_UNSPECIFIED:
+		case s.marks[userID]:
+			score = 1
+		default:
+			score = 0
+		}
+
+		// Both players are rated against the other's rating from before the game.
+		r, rd, vol := glicko2(rating, []glickoResult{{
+			rating:    opponent.Rating,
+			deviation: opponent.Deviation,
+			score:     score,
+		}})
+		change := &api.RatingChange{
+			MatchId:      matchID,
+			OpponentId:   opponentID,
+			Score:        score,
+			Reason:       s.doneReason,
+			RatingBefore: rating.Rating,
+			RatingAfter:  r,
+			Time:         t.Unix(),
+		}
+		recent := append([]*api.RatingChange{change}, rating.Recent...)
+		if len(recent) > maxRecentRatingChanges {
+			recent = recent[:maxRecentRatingChanges]
+		}
+		updated := &api.Rating{
+			Rating:      r,
+			Deviation:   rd,
+			Volatility:  vol,
+			GamesPlayed: rating.GamesPlayed + 1,
+			Recent:      recent,
+		}
+
+		var buf bytes.Buffer
+		if err := m.marshaler.Marshal(&buf, updated); err != nil {
+			return err
+		}
+		writes = append(writes, &runtime.StorageWrite{
+			Collection:      ratingCollection,
+			Key:             key,
+			UserID:          userID,
+			Value:           buf.String(),
+			Version:         versions[userID],
+			PermissionRead:  2, // Public read.
+			PermissionWrite: 0, // No client write.
+		})
+	}
+
+	_, _, err = nk.MultiUpdate(ctx, nil, writes, nil, false)
+	return err
+}
+
+// Fetch a player's ratings in both modes of play, along with their recent changes.
+func rpcGetRating(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		callerID, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string)
+		if !ok {
+			return "", errNoUserIdFound
+		}
+
+		request := &api.RpcGetRatingRequest{}
+		if len(payload) > 0 {
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+				return "", errUnmarshal
+			}
+		}
+		userID := request.UserId
+		if userID == "" {
+			userID = callerID
+		}
+
+		objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{
+			{Collection: ratingCollection, Key: ratingKeyFast, UserID: userID},
+			{Collection: ratingCollection, Key: ratingKeyNormal, UserID: userID},
+		})
+		if err != nil {
+			logger.Error("StorageRead error: %v", err)
+			return "", errInternalError
+		}
+
+		resp := &api.RpcGetRatingResponse{
+			UserId: userID,
+			Fast:   newRating(),
+			Normal: newRating(),
+		}
+		for _, object := range objects {
+			rating := &api.Rating{}
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(object.GetValue())), rating); err != nil {
+				logger.Error("error decoding rating: %v", err)
+				return "", errInternalError
+			}
+			if object.GetKey() == ratingKeyFast {
+				resp.Fast = rating
+			} else {
+				resp.Normal = rating
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, resp); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+		return buf.String(), nil
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+// The worked example from the Glicko-2 paper.
+func TestGlicko2(t *testing.T) {
+	rating := &api.Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	r, rd, vol := glicko2(rating, []glickoResult{
+		{rating: 1400, deviation: 30, score: 1},
+		{rating: 1550, deviation: 100, score: 0},
+		{rating: 1700, deviation: 300, score: 0},
+	})
+	if math.Abs(r-1464.06) > 0.01 || math.Abs(rd-151.52) > 0.01 || math.Abs(vol-0.05999) > 0.00001 {
+		t.Errorf("glicko2 = (%v, %v, %v), want (1464.06, 151.52, 0.05999)", r, rd, vol)
+	}
+
+	r, rd, _ = glicko2(rating, nil)
+	if r != rating.Rating || rd <= rating.Deviation {
+		t.Errorf("glicko2 without games = (%v, %v), want the same rating and a larger deviation", r, rd)
+	}
+}
+
+func TestMatchRatings(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		// Positions played in turn, starting with X. Games the moves don't decide end when the player to move times out.
+		moves []int32
+		rated bool
+		// Whether X's rating should go up, stay the same or go down.
+		xChange int
+	}{
+		{
+			name:    "X wins",
+			params:  map[string]interface{}{},
+			moves:   []int32{0, 3, 1, 4, 2},
+			rated:   true,
+			xChange: 1,
+		},
+		{
+			name:    "tie",
+			params:  map[string]interface{}{},
+			moves:   []int32{0, 1, 2, 4, 3, 5, 7, 6, 8},
+			rated:   true,
+			xChange: 0,
+		},
+		{
+			name:    "X times out",
+			params:  map[string]interface{}{},
+			rated:   true,
+			xChange: -1,
+		},
+		{
+			name:   "private match",
+			params: map[string]interface{}{"private_code": "ABC234"},
+			moves:  []int32{0, 3, 1, 4, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, tt.params)
+			x, o := startTestGame(t, d)
+
+			players := []*matchtest.Presence{x, o}
+			for i, position := range tt.moves {
+				d.Step(moveMessage(players[i%2], position))
+			}
+			done := func() bool { return d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)) != nil }
+			if !d.StepUntil(turnTimeNormalSec*tickRate+1, done) {
+				t.Fatal("game did not end")
+			}
+
+			xRating, oRating := testRating(t, d.NK, x.UserID, ratingKeyFast), testRating(t, d.NK, o.UserID, ratingKeyFast)
+			if !tt.rated {
+				if xRating != nil || oRating != nil {
+					t.Error("unrated game changed ratings")
+				}
+				return
+			}
+			if xRating == nil || oRating == nil {
+				t.Fatal("ratings not written")
+			}
+			if testRating(t, d.NK, x.UserID, ratingKeyNormal) != nil {
+				t.Error("fast game rated in normal mode")
+			}
+
+			for _, rating := range []*api.Rating{xRating, oRating} {
+				if rating.GamesPlayed != 1 || len(rating.Recent) != 1 || rating.Recent[0].MatchId != testMatchID {
+					t.Errorf("rating = %+v, want one game in match %v", rating, testMatchID)
+				}
+				if rating.Deviation >= defaultDeviation {
+					t.Errorf("deviation = %v, want less than %v", rating.Deviation, defaultDeviation)
+				}
+			}
+			if change := xRating.Rating - defaultRating; !sameSign(change, tt.xChange) {
+				t.Errorf("X rating changed by %v", change)
+			}
+			// Players of equal rating gain and lose the same amount.
+			if math.Abs(xRating.Rating+oRating.Rating-2*defaultRating) > 0.000001 {
+				t.Errorf("ratings = %v and %v, want symmetric changes", xRating.Rating, oRating.Rating)
+			}
+		})
+	}
+}
+
+func TestMatchRatingsAccumulate(t *testing.T) {
+	nk := matchtest.NewNakamaModule()
+	for game := 1; game <= 2; game++ {
+		d := matchtest.NewDriver(newTestHandler(), testMatchID, nk, matchtest.NewLogger(t.Logf))
+		if !d.Init(map[string]interface{}{"fast": true}) {
+			t.Fatal("match init failed")
+		}
+		x, o := startTestGame(t, d)
+		for i, position := range []int32{0, 3, 1, 4, 2} {
+			d.Step(moveMessage([]*matchtest.Presence{x, o}[i%2], position))
+		}
+
+		winner := testRating(t, nk, x.UserID, ratingKeyFast)
+		if winner == nil || winner.GamesPlayed != int32(game) || len(winner.Recent) != game {
+			t.Fatalf("rating after game %d = %+v", game, winner)
+		}
+		if winner.Recent[0].RatingBefore == winner.Recent[0].RatingAfter || winner.Recent[0].OpponentId != o.UserID {
+			t.Errorf("latest change = %+v", winner.Recent[0])
+		}
+	}
+}
+
+func TestRpcGetRating(t *testing.T) {
+	nk := matchtest.NewNakamaModule()
+	d := matchtest.NewDriver(newTestHandler(), testMatchID, nk, matchtest.NewLogger(t.Logf))
+	if !d.Init(map[string]interface{}{"fast": true}) {
+		t.Fatal("match init failed")
+	}
+	x, o := startTestGame(t, d)
+	for i, position := range []int32{0, 3, 1, 4, 2} {
+		d.Step(moveMessage([]*matchtest.Presence{x, o}[i%2], position))
+	}
+
+	marshaler := &jsonpb.Marshaler{EnumsAsInts: true}
+	unmarshaler := &jsonpb.Unmarshaler{}
+	rpc := rpcGetRating(marshaler, unmarshaler)
+	ctx := context.WithValue(context.Background(), runtime.RUNTIME_CTX_USER_ID, x.UserID)
+
+	tests := []struct {
+		name    string
+		payload string
+		userID  string
+	}{
+		{name: "own rating", payload: "", userID: x.UserID},
+		{name: "another player's rating", payload: `{"user_id":"` + o.UserID + `"}`, userID: o.UserID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := rpc(ctx, matchtest.NewLogger(t.Logf), nil, nk, tt.payload)
+			if err != nil {
+				t.Fatalf("get rating error: %v", err)
+			}
+			resp := &api.RpcGetRatingResponse{}
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), resp); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if resp.UserId != tt.userID || resp.Fast.GamesPlayed != 1 || len(resp.Fast.Recent) != 1 {
+				t.Errorf("fast rating = %+v", resp.Fast)
+			}
+			if resp.Normal.Rating != defaultRating || resp.Normal.GamesPlayed != 0 {
+				t.Errorf("normal rating = %+v, want the starting rating", resp.Normal)
+			}
+		})
+	}
+}
+
+func testRating(t *testing.T, nk *matchtest.NakamaModule, userID, key string) *api.Rating {
+	t.Helper()
+	object := nk.StorageObject(ratingCollection, key, userID)
+	if object == nil {
+		return nil
+	}
+	rating := &api.Rating{}
+	if err := jsonpb.Unmarshal(bytes.NewReader([]byte(object.GetValue())), rating); err != nil {
+		t.Fatalf("error decoding rating: %v", err)
+	}
+	return rating
+}
+
+func sameSign(f float64, sign int) bool {
+	switch {
+	case sign > 0:
+		return f > 0
+	case sign < 0:
+		return f < 0
+	}
+	return math.Abs(f) < 0.000001
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const defaultReconnectWindowSec = 20
+
+// Give a player who dropped out of the game in progress a window to come back, pausing the turn timer meanwhile.
+func (m *MatchHandler) startReconnectWindow(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, userID string, t time.Time) {
+	if !s.playing || s.reconnectWindowTicks == 0 || s.marks[userID] == api.Mark_MARK_UNSPECIFIED {
+		return
+	}
+
+	s.reconnectRemainingTicks[userID] = s.reconnectWindowTicks
+	m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_OPPONENT_DISCONNECTED, &api.OpponentDisconnected{
+		UserId:   userID,
+		Deadline: t.Add(time.Duration(s.reconnectWindowTicks/tickRate) * time.Second).Unix(),
+		Sequence: s.nextSequence(),
+	}, nil)
+}
+
+// Close the reconnect window of a player who returned in time, resuming the turn timer once nobody else is missing.
+func (m *MatchHandler) endReconnectWindow(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, userID string, t time.Time) {
+	if _, ok := s.reconnectRemainingTicks[userID]; !ok {
+		return
+	}
+
+	delete(s.reconnectRemainingTicks, userID)
+	m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_OPPONENT_RECONNECTED, &api.OpponentReconnected{
+		UserId:   userID,
+		Deadline: t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix(),
+		Sequence: s.nextSequence(),
+	}, nil)
+}
+
+// Count down the reconnect windows of disconnected players. Returns the user IDs of the players whose window ran out,
+// in sorted order, and closes their windows.
+func (s *MatchState) expiredReconnectWindows() []string {
+	expired := make([]string, 0)
+	for userID := range s.reconnectRemainingTicks {
+		s.reconnectRemainingTicks[userID]--
+		if s.reconnectRemainingTicks[userID] <= 0 {
+			expired = append(expired, userID)
+			delete(s.reconnectRemainingTicks, userID)
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// End the game for players who ran out of time to reconnect. They forfeit in turn, unless nobody still in the game came
+// back, which abandons the game without a winner.
+func (m *MatchHandler) expireReconnectWindows(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, s *MatchState, t time.Time, expired []string) {
+	remaining := 0
+	for userID := range s.marks {
+		if !s.eliminated[userID] {
+			remaining++
+		}
+	}
+	if len(expired) >= remaining {
+		m.endGame(ctx, logger, nk, dispatcher, s, t, api.Mark_MARK_UNSPECIFIED, nil, api.DoneReason_DONE_REASON_ABANDONED)
+		return
+	}
+
+	for _, userID := range expired {
+		if !s.playing {
+			return
+		}
+		m.forfeit(ctx, logger, nk, dispatcher, s, t, userID, nil, api.DoneReason_DONE_REASON_DISCONNECT)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const rematchTimeoutSec = 15
+
+// Open a vote on whether the players of the game that just ended want to play again.
+func (s *MatchState) startRematch() {
+	s.rematchPending = true
+	s.rematchRemainingTicks = rematchTimeoutSec * tickRate
+	s.rematchVotes = make(map[string]bool, 2)
+
+	// The bot is always up for another game.
+	if _, ok := s.marks[botUserID]; ok {
+		s.rematchVotes[botUserID] = true
+	}
+}
+
+// Count rematch votes received this tick. The next game starts only once every remaining player has accepted. Players
+// who decline, or don't vote in time, give up their slot so the match can be offered to someone else.
+func (m *MatchHandler) processRematch(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, messages []runtime.MatchData) {
+	declined := make([]string, 0, 1)
+	for _, message := range messages {
+		userID := message.GetUserId()
+		if s.marks[userID] == api.Mark_MARK_UNSPECIFIED || s.presences[userID] == nil {
+			// Only players from the game that just ended get a vote.
+			continue
+		}
+
+		switch api.OpCode(message.GetOpCode()) {
+		case api.OpCode_OPCODE_REMATCH_ACCEPT:
+			s.rematchVotes[userID] = true
+			m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_REMATCH_ACCEPT, &api.Rematch{UserId: userID, Sequence: s.nextSequence()}, nil)
+		case api.OpCode_OPCODE_REMATCH_DECLINE:
+			m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_REMATCH_DECLINE, &api.Rematch{UserId: userID, Sequence: s.nextSequence()}, nil)
+			declined = append(declined, userID)
+		}
+	}
+
+	// Players who haven't made up their mind by the deadline are treated as declining.
+	s.rematchRemainingTicks--
+	if len(declined) == 0 && s.rematchRemainingTicks <= 0 {
+		for userID := range s.presences {
+			if !s.rematchVotes[userID] {
+				m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_REMATCH_DECLINE, &api.Rematch{UserId: userID, Timeout: true, Sequence: s.nextSequence()}, nil)
+				declined = append(declined, userID)
+			}
+		}
+	}
+
+	if len(declined) > 0 {
+		s.rematchPending = false
+		for _, userID := range declined {
+			presence := s.presences[userID]
+			delete(s.presences, userID)
+			if presence != nil && userID != botUserID {
+				if err := dispatcher.MatchKick([]runtime.Presence{presence}); err != nil {
+					logger.Error("error kicking player who declined rematch: %v", err)
+				}
+			}
+		}
+		return
+	}
+
+	for userID := range s.presences {
+		if !s.rematchVotes[userID] {
+			return
+		}
+	}
+	s.rematchPending = false
+}
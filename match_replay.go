@@ -0,0 +1,215 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// System-owned full game records, readable by anyone who knows the replay ID.
+	replayCollection = "replay"
+	// Per-player summaries of the games they took part in.
+	replayIndexCollection = "replay_index"
+
+	defaultReplayListLimit = 10
+	maxReplayListLimit     = 100
+)
+
+var errReplayNotFound = runtime.NewError("replay not found", 5) // NOT_FOUND
+
+// Begin recording a new game round.
+func (s *MatchState) startReplay(matchID string, tick int64, t time.Time) {
+	s.gamesPlayed++
+	s.gameStartTick = tick
+	s.replay = &api.Replay{
+		ReplayId:  fmt.Sprintf("%v-%d", matchID, s.gamesPlayed),
+		MatchId:   matchID,
+		BoardSize: int32(s.boardSize),
+		WinLength: int32(s.winLength),
+		Marks:     s.marks,
+		TickRate:  tickRate,
+		StartTime: t.Unix(),
+
+		FirstMoveReason: s.firstMoveReason,
+		Seed:            s.seed,
+		Ultimate:        s.ultimate,
+		Variant:         s.variant,
+	}
+}
+
+// Record an accepted move in the current game's replay.
+func (s *MatchState) recordMove(userID string, mark api.Mark, position int32, tick int64) {
+	if s.replay == nil {
+		return
+	}
+	s.replay.Moves = append(s.replay.Moves, &api.ReplayMove{
+		UserId:   userID,
+		Mark:     mark,
+		Position: position,
+		Tick:     tick - s.gameStartTick,
+	})
+}
+
+// Write the finished game to storage, along with a summary in the history of each player that took part.
+func (m *MatchHandler) saveReplay(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState, t time.Time) {
+	replay := s.replay
+	if replay == nil {
+		return
+	}
+	s.replay = nil
+
+	replay.Winner = s.winner
+	replay.WinnerPositions = s.winnerPositions
+	replay.Reason = s.doneReason
+	replay.EndTime = t.Unix()
+
+	var buf bytes.Buffer
+	if err := m.marshaler.Marshal(&buf, replay); err != nil {
+		logger.Error("error encoding replay: %v", err)
+		return
+	}
+	writes := []*runtime.StorageWrite{{
+		Collection:      replayCollection,
+		Key:             replay.ReplayId,
+		PermissionRead:  2, // Public read.
+		PermissionWrite: 0, // No client write.
+		Value:           buf.String(),
+	}}
+
+	summary := &api.ReplaySummary{
+		ReplayId: replay.ReplayId,
+		Marks:    replay.Marks,
+		Winner:   replay.Winner,
+		EndTime:  replay.EndTime,
+	}
+	buf.Reset()
+	if err := m.marshaler.Marshal(&buf, summary); err != nil {
+		logger.Error("error encoding replay summary: %v", err)
+		return
+	}
+	// Keys sort newest first, so listing a player's history pages back through time.
+	indexKey := fmt.Sprintf("%019d-%v", math.MaxInt64-t.UnixNano(), replay.ReplayId)
+	for userID := range replay.Marks {
+		if userID == botUserID {
+			continue
+		}
+		writes = append(writes, &runtime.StorageWrite{
+			Collection:      replayIndexCollection,
+			Key:             indexKey,
+			UserID:          userID,
+			PermissionRead:  1, // Owner read.
+			PermissionWrite: 0, // No client write.
+			Value:           buf.String(),
+		})
+	}
+
+	if _, err := nk.StorageWrite(ctx, writes); err != nil {
+		logger.Error("error writing replay: %v", err)
+	}
+}
+
+// Fetch a recorded game by its replay ID.
+func rpcGetReplay(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		if _, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string); !ok {
+			return "", errNoUserIdFound
+		}
+
+		request := &api.RpcGetReplayRequest{}
+		if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+			return "", errUnmarshal
+		}
+		if request.ReplayId == "" {
+			return "", errBadInput
+		}
+
+		objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+			Collection: replayCollection,
+			Key:        request.ReplayId,
+		}})
+		if err != nil {
+			logger.Error("StorageRead error: %v", err)
+			return "", errInternalError
+		}
+		if len(objects) == 0 {
+			return "", errReplayNotFound
+		}
+
+		// Replays are stored in their wire format already.
+		return objects[0].GetValue(), nil
+	}
+}
+
+// Page through summaries of the caller's recent games, newest first.
+func rpcListReplays(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error) {
+	return func(ctx context.Context, logger runtime.Logger, db *sql.DB, nk runtime.NakamaModule, payload string) (string, error) {
+		userID, ok := ctx.Value(runtime.RUNTIME_CTX_USER_ID).(string)
+		if !ok {
+			return "", errNoUserIdFound
+		}
+
+		request := &api.RpcListReplaysRequest{}
+		if len(payload) > 0 {
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(payload)), request); err != nil {
+				return "", errUnmarshal
+			}
+		}
+
+		limit := int(request.Limit)
+		if limit == 0 {
+			limit = defaultReplayListLimit
+		}
+		if limit < 0 || limit > maxReplayListLimit {
+			return "", errBadInput
+		}
+
+		objects, cursor, err := nk.StorageList(ctx, userID, replayIndexCollection, limit, request.Cursor)
+		if err != nil {
+			logger.Error("StorageList error: %v", err)
+			return "", errInternalError
+		}
+
+		resp := &api.RpcListReplaysResponse{
+			Replays: make([]*api.ReplaySummary, 0, len(objects)),
+			Cursor:  cursor,
+		}
+		for _, object := range objects {
+			summary := &api.ReplaySummary{}
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(object.GetValue())), summary); err != nil {
+				logger.Error("error decoding replay summary: %v", err)
+				return "", errUnmarshal
+			}
+			resp.Replays = append(resp.Replays, summary)
+		}
+
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, resp); err != nil {
+			logger.Error("Marshal error: %v", err)
+			return "", errMarshal
+		}
+
+		return buf.String(), nil
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+// Play a game won by X in each of the matches, all between alice and bob.
+func playTestReplays(t *testing.T, nk *matchtest.NakamaModule, matchIDs ...string) {
+	t.Helper()
+	for _, matchID := range matchIDs {
+		d := matchtest.NewDriver(newTestHandler(), matchID, nk, matchtest.NewLogger(t.Logf))
+		if !d.Init(map[string]interface{}{"fast": true}) {
+			t.Fatal("match init failed")
+		}
+		x, o := startTestGame(t, d)
+		for i, position := range []int32{0, 3, 1, 4, 2} {
+			d.Step(moveMessage([]*matchtest.Presence{x, o}[i%2], position))
+		}
+		if d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)) == nil {
+			t.Fatalf("game in %v did not end", matchID)
+		}
+	}
+}
+
+func userContext(userID string) context.Context {
+	return context.WithValue(context.Background(), runtime.RUNTIME_CTX_USER_ID, userID)
+}
+
+func TestRpcGetReplay(t *testing.T) {
+	nk := matchtest.NewNakamaModule()
+	playTestReplays(t, nk, "match-a.node")
+	rpc := rpcGetReplay(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		payload string
+		err     error
+	}{
+		{name: "player's own game", ctx: userContext("alice"), payload: `{"replay_id":"match-a.node-1"}`},
+		{name: "anyone may watch", ctx: userContext("carol"), payload: `{"replay_id":"match-a.node-1"}`},
+		{name: "unknown replay", ctx: userContext("alice"), payload: `{"replay_id":"match-z.node-1"}`, err: errReplayNotFound},
+		{name: "missing replay ID", ctx: userContext("alice"), payload: `{}`, err: errBadInput},
+		{name: "malformed payload", ctx: userContext("alice"), payload: `{"replay_id":`, err: errUnmarshal},
+		{name: "without a user", ctx: context.Background(), payload: `{"replay_id":"match-a.node-1"}`, err: errNoUserIdFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := rpc(tt.ctx, matchtest.NewLogger(t.Logf), nil, nk, tt.payload)
+			if err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			replay := &api.Replay{}
+			if err := jsonpb.Unmarshal(bytes.NewReader([]byte(payload)), replay); err != nil {
+				t.Fatalf("error decoding replay: %v", err)
+			}
+			if replay.ReplayId != "match-a.node-1" || len(replay.Moves) != 5 || replay.Winner != api.Mark_MARK_X {
+				t.Errorf("replay = %+v", replay)
+			}
+		})
+	}
+}
+
+func TestRpcListReplays(t *testing.T) {
+	nk := matchtest.NewNakamaModule()
+	playTestReplays(t, nk, "match-a.node", "match-b.node", "match-c.node")
+	rpc := rpcListReplays(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+
+	// Each player's history is only readable by them.
+	objects, _, _ := nk.StorageList(context.Background(), "alice", replayIndexCollection, 0, "")
+	if len(objects) != 3 {
+		t.Fatalf("alice has %d replay summaries, want 3", len(objects))
+	}
+	for _, object := range objects {
+		if object.GetPermissionRead() != 1 || object.GetPermissionWrite() != 0 {
+			t.Errorf("replay index permissions = (%v, %v), want owner read only", object.GetPermissionRead(), object.GetPermissionWrite())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		payload string
+		err     error
+		// Match IDs of the listed replays, newest first.
+		matchIDs []string
+		more     bool
+	}{
+		{name: "default limit", ctx: userContext("alice"), payload: "", matchIDs: []string{"match-c.node", "match-b.node", "match-a.node"}},
+		{name: "first page", ctx: userContext("bob"), payload: `{"limit":2}`, matchIDs: []string{"match-c.node", "match-b.node"}, more: true},
+		{name: "player without games", ctx: userContext("carol"), payload: `{}`, matchIDs: []string{}},
+		{name: "negative limit", ctx: userContext("alice"), payload: `{"limit":-1}`, err: errBadInput},
+		{name: "limit too large", ctx: userContext("alice"), payload: `{"limit":101}`, err: errBadInput},
+		{name: "without a user", ctx: context.Background(), payload: "", err: errNoUserIdFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := listTestReplays(t, rpc, tt.ctx, nk, tt.payload)
+			if err != tt.err {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			checkTestReplays(t, resp, tt.matchIDs)
+			if (resp.Cursor != "") != tt.more {
+				t.Errorf("cursor = %q, want more pages %v", resp.Cursor, tt.more)
+			}
+		})
+	}
+
+	t.Run("next page", func(t *testing.T) {
+		first, err := listTestReplays(t, rpc, userContext("bob"), nk, `{"limit":2}`)
+		if err != nil {
+			t.Fatalf("error listing first page: %v", err)
+		}
+		resp, err := listTestReplays(t, rpc, userContext("bob"), nk, `{"limit":2,"cursor":"`+first.Cursor+`"}`)
+		if err != nil {
+			t.Fatalf("error listing next page: %v", err)
+		}
+		checkTestReplays(t, resp, []string{"match-a.node"})
+		if resp.Cursor != "" {
+			t.Errorf("cursor = %q after the last page", resp.Cursor)
+		}
+	})
+}
+
+func listTestReplays(t *testing.T, rpc func(context.Context, runtime.Logger, *sql.DB, runtime.NakamaModule, string) (string, error), ctx context.Context, nk runtime.NakamaModule, payload string) (*api.RpcListReplaysResponse, error) {
+	t.Helper()
+	out, err := rpc(ctx, matchtest.NewLogger(t.Logf), nil, nk, payload)
+	if err != nil {
+		return nil, err
+	}
+	resp := &api.RpcListReplaysResponse{}
+	if err := jsonpb.Unmarshal(bytes.NewReader([]byte(out)), resp); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	return resp, nil
+}
+
+func checkTestReplays(t *testing.T, resp *api.RpcListReplaysResponse, matchIDs []string) {
+	t.Helper()
+	if len(resp.Replays) != len(matchIDs) {
+		t.Fatalf("listed %d replays, want %d", len(resp.Replays), len(matchIDs))
+	}
+	for i, summary := range resp.Replays {
+		if summary.ReplayId != matchIDs[i]+"-1" || summary.Winner != api.Mark_MARK_X {
+			t.Errorf("replay %d = %+v, want %v", i, summary, matchIDs[i])
+		}
+	}
+}
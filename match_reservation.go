@@ -0,0 +1,302 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// System-owned seat reservations, keyed by match ID.
+	seatReservationCollection = "match_reservation"
+	// System-owned latest matches created for each kind of game.
+	matchPoolCollection = "match_pool"
+
+	// How long a seat is held for a player who found a match but hasn't joined it yet.
+	seatReservationExpirySec = 15
+	// How long players who find no match are steered into the match someone else just created.
+	matchPoolExpirySec = 15
+	// Writes conflicting with another player's are retried from a fresh read this many times.
+	reservationAttempts = 3
+
+	seatReservationTokenBytes = 16
+)
+
+// Read the unexpired seat reservations of a match, along with the version of their storage object.
+func readSeatReservations(ctx context.Context, nk runtime.NakamaModule, unmarshaler *jsonpb.Unmarshaler, matchID string, t time.Time) (*api.SeatReservations, string, error) {
+	objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+		Collection: seatReservationCollection,
+		Key:        matchID,
+	}})
+	if err != nil {
+		return nil, "", err
+	}
+	reservations := &api.SeatReservations{}
+	if len(objects) == 0 {
+		// Only create the object if no other player does first.
+		return reservations, "*", nil
+	}
+	if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(objects[0].GetValue())), reservations); err != nil {
+		return nil, "", err
+	}
+
+	live := reservations.Reservations[:0]
+	for _, r := range reservations.Reservations {
+		if r.ExpireTime > t.Unix() {
+			live = append(live, r)
+		}
+	}
+	reservations.Reservations = live
+	return reservations, objects[0].GetVersion(), nil
+}
+
+// Store a match's seat reservations, only if the stored object still matches the given version.
+func writeSeatReservations(ctx context.Context, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, matchID, version string, reservations *api.SeatReservations) error {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, reservations); err != nil {
+		return err
+	}
+	_, err := nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+		Collection:      seatReservationCollection,
+		Key:             matchID,
+		Value:           buf.String(),
+		Version:         version,
+		PermissionRead:  0, // No client read.
+		PermissionWrite: 0, // No client write.
+	}})
+	return err
+}
+
+// Hold a seat in the match for the user, if the players in it and the seats held for others leave one free. Returns
+// the token the user claims the seat with, or nothing if the match is full.
+func reserveSeat(ctx context.Context, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler, matchID, userID string, seats, size int, t time.Time) (string, error) {
+	var err error
+	for attempt := 0; attempt < reservationAttempts; attempt++ {
+		var reservations *api.SeatReservations
+		var version string
+		if reservations, version, err = readSeatReservations(ctx, nk, unmarshaler, matchID, t); err != nil {
+			return "", err
+		}
+
+		// A user searching again replaces the seat they were already holding.
+		held := reservations.Reservations[:0]
+		for _, r := range reservations.Reservations {
+			if r.UserId != userID {
+				held = append(held, r)
+			}
+		}
+		if size+len(held) >= seats {
+			return "", nil
+		}
+
+		var token string
+		if token, err = newSeatReservationToken(); err != nil {
+			return "", err
+		}
+		reservations.Reservations = append(held, &api.SeatReservation{
+			Token:      token,
+			UserId:     userID,
+			ExpireTime: t.Add(seatReservationExpirySec * time.Second).Unix(),
+		})
+		if err = writeSeatReservations(ctx, nk, marshaler, matchID, version, reservations); err == nil {
+			return token, nil
+		}
+	}
+	return "", err
+}
+
+// Release the seats held for users who have now joined the match. The reservations read by the join attempt are used
+// first, and only read again if a search has changed them since.
+func (m *MatchHandler) claimSeatReservations(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState, userIDs map[string]bool, t time.Time) {
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	reservations, version := s.seatReservations, s.seatReservationsVersion
+	s.seatReservations, s.seatReservationsVersion = nil, ""
+	if reservations == nil {
+		// Nobody joined through a join attempt that read them.
+		return
+	}
+
+	var err error
+	for attempt := 0; attempt < reservationAttempts; attempt++ {
+		if attempt > 0 {
+			if reservations, version, err = readSeatReservations(ctx, nk, m.unmarshaler, matchID, t); err != nil {
+				break
+			}
+		}
+
+		held := make([]*api.SeatReservation, 0, len(reservations.Reservations))
+		for _, r := range reservations.Reservations {
+			if !userIDs[r.UserId] {
+				held = append(held, r)
+			}
+		}
+		if version == "*" || len(held) == len(reservations.Reservations) {
+			// None of the users had a seat held for them.
+			return
+		}
+		if err = writeSeatReservations(ctx, nk, m.marshaler, matchID, version, &api.SeatReservations{Reservations: held}); err == nil {
+			return
+		}
+	}
+	logger.Error("error claiming seat reservations: %v", err)
+}
+
+// Delete the seat reservations of a match that is about to close.
+func releaseSeatReservations(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule) {
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	if err := nk.StorageDelete(ctx, []*runtime.StorageDelete{{
+		Collection: seatReservationCollection,
+		Key:        matchID,
+	}}); err != nil {
+		logger.Error("error deleting seat reservations: %v", err)
+	}
+}
+
+// The pool of players who find no match for the same kind of game share the latest match any of them created.
+func matchPoolKey(module string, fast bool, boardSize, winLength, seriesLength int, variant string, seats int, timeControl string) string {
+	return fmt.Sprintf("%v-%v-%d-%d-%d-%v-%d-%v", module, fast, boardSize, winLength, seriesLength, variant, seats, timeControl)
+}
+
+// Hold a seat for the user in the match another player in the pool just created, if its rating is within the window.
+// Otherwise send them back to the match they created earlier in their search, if it still has their seat, or create a
+// match with the params and make it the pool's latest, so players racing to create a match at the same time meet in
+// one. Returns the match ID, the seat reservation token unless the user is already waiting in their own match, and
+// whether the match is the user's own.
+func findOrCreatePoolMatch(ctx context.Context, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler, poolKey, module string, params map[string]interface{}, userID, ownMatchID string, seats, rating, window int, t time.Time) (string, string, bool, error) {
+	var matchID, token string
+	for attempt := 0; attempt < reservationAttempts; attempt++ {
+		objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+			Collection: matchPoolCollection,
+			Key:        poolKey,
+		}})
+		if err != nil {
+			return "", "", false, err
+		}
+
+		version := "*"
+		if len(objects) > 0 {
+			version = objects[0].GetVersion()
+			entry := &api.MatchPoolEntry{}
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(objects[0].GetValue())), entry); err != nil {
+				return "", "", false, err
+			}
+			if entry.UserId != userID && t.Unix()-entry.CreateTime <= matchPoolExpirySec && (window == 0 || abs(int(entry.Rating)-rating) <= window) {
+				match, err := nk.MatchGet(ctx, entry.MatchId)
+				if err != nil {
+					return "", "", false, err
+				}
+				if match != nil {
+					poolToken, err := reserveSeat(ctx, nk, marshaler, unmarshaler, entry.MatchId, userID, seats, int(match.GetSize()), t)
+					if err != nil {
+						return "", "", false, err
+					}
+					if poolToken != "" {
+						// Any match created on an earlier attempt is left to close once nobody joins it.
+						return entry.MatchId, poolToken, false, nil
+					}
+				}
+			}
+		}
+
+		if matchID == "" && ownMatchID != "" {
+			match, err := nk.MatchGet(ctx, ownMatchID)
+			if err != nil {
+				return "", "", false, err
+			}
+			if match != nil {
+				// A user already waiting in their match needs no seat held, and holding one would keep it from others.
+				reservations, _, err := readSeatReservations(ctx, nk, unmarshaler, ownMatchID, t)
+				if err != nil {
+					return "", "", false, err
+				}
+				held := false
+				for _, r := range reservations.Reservations {
+					held = held || r.UserId == userID
+				}
+				if !held && int(match.GetSize()) < seats {
+					return ownMatchID, "", true, nil
+				}
+				if held {
+					ownToken, err := reserveSeat(ctx, nk, marshaler, unmarshaler, ownMatchID, userID, seats, int(match.GetSize()), t)
+					if err != nil {
+						return "", "", false, err
+					}
+					if ownToken != "" {
+						return ownMatchID, ownToken, true, nil
+					}
+				}
+			}
+			// Gone or taken by others, start over with a new match.
+			ownMatchID = ""
+		}
+
+		if matchID == "" {
+			if matchID, err = nk.MatchCreate(ctx, module, params); err != nil {
+				return "", "", false, err
+			}
+			if token, err = reserveSeat(ctx, nk, marshaler, unmarshaler, matchID, userID, seats, 0, t); err != nil {
+				return "", "", false, err
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, &api.MatchPoolEntry{
+			MatchId:    matchID,
+			Rating:     int32(rating),
+			CreateTime: t.Unix(),
+			UserId:     userID,
+		}); err != nil {
+			return "", "", false, err
+		}
+		if _, err := nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+			Collection:      matchPoolCollection,
+			Key:             poolKey,
+			Value:           buf.String(),
+			Version:         version,
+			PermissionRead:  0, // No client read.
+			PermissionWrite: 0, // No client write.
+		}}); err == nil {
+			return matchID, token, true, nil
+		}
+		// Another player in the pool created a match at the same time, try to join theirs instead.
+	}
+
+	// Still racing with others after every attempt, the new match is as good as any.
+	return matchID, token, true, nil
+}
+
+func newSeatReservationToken() (string, error) {
+	b := make([]byte, seatReservationTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
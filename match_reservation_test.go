@@ -0,0 +1,243 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestReserveSeat(t *testing.T) {
+	ctx := context.Background()
+	nk := matchtest.NewNakamaModule()
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	start := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name   string
+		userID string
+		// Players already in the match.
+		size  int
+		after time.Duration
+		held  bool
+	}{
+		{name: "first seat", userID: "alice", held: true},
+		{name: "last seat", userID: "bob", held: true},
+		{name: "seats all held", userID: "carol", held: false},
+		{name: "holder searching again", userID: "alice", held: true},
+		{name: "player joined besides the held seats", userID: "carol", size: 1, held: false},
+		{name: "reservations expired", userID: "carol", size: 1, after: (seatReservationExpirySec + 1) * time.Second, held: true},
+		{name: "match full", userID: "dave", size: 2, after: (seatReservationExpirySec + 1) * time.Second, held: false},
+	}
+
+	tokens := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := reserveSeat(ctx, nk, marshaler, unmarshaler, testMatchID, tt.userID, minSeats, tt.size, start.Add(tt.after))
+			if err != nil {
+				t.Fatalf("reserve seat error: %v", err)
+			}
+			if (token != "") != tt.held {
+				t.Errorf("token = %q, want held %v", token, tt.held)
+			}
+			if token != "" && tokens[token] {
+				t.Errorf("token %q handed out twice", token)
+			}
+			tokens[token] = true
+		})
+	}
+}
+
+func TestMatchJoinAttemptReservations(t *testing.T) {
+	d := newTestMatch(t, map[string]interface{}{})
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	reserve := func(userID string) string {
+		token, err := reserveSeat(d.Ctx, d.NK, marshaler, unmarshaler, testMatchID, userID, minSeats, 0, time.Now().UTC())
+		if err != nil || token == "" {
+			t.Fatalf("no seat reserved for %v: %v", userID, err)
+		}
+		return token
+	}
+	alice, bob := reserve("alice"), reserve("bob")
+
+	tests := []struct {
+		name     string
+		userID   string
+		metadata map[string]string
+		// Whether storage fails while the user tries to join.
+		readErr  bool
+		accepted bool
+		reason   string
+	}{
+		{name: "reservations unreadable", userID: "alice", metadata: map[string]string{"reservation": alice}, readErr: true, accepted: false, reason: "try again"},
+		{name: "without a reservation", userID: "mallory", accepted: false, reason: "match full"},
+		{name: "someone else's reservation", userID: "mallory", metadata: map[string]string{"reservation": alice}, accepted: false, reason: "reservation expired"},
+		{name: "holder with the wrong token", userID: "alice", metadata: map[string]string{"reservation": bob}, accepted: false, reason: "reservation expired"},
+		{name: "holder", userID: "alice", metadata: map[string]string{"reservation": alice}, accepted: true},
+		{name: "other holder", userID: "bob", metadata: map[string]string{"reservation": bob}, accepted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.readErr {
+				d.NK.StorageReadErr = errors.New("storage unavailable")
+				defer func() { d.NK.StorageReadErr = nil }()
+			}
+			accepted, reason := d.Join(matchtest.NewPresence(tt.userID), tt.metadata)
+			if accepted != tt.accepted || reason != tt.reason {
+				t.Errorf("join = (%v, %q), want (%v, %q)", accepted, reason, tt.accepted, tt.reason)
+			}
+		})
+	}
+
+	// Joining claims the held seats, and closing the match forgets about them.
+	reservations, _, err := readSeatReservations(d.Ctx, d.NK, unmarshaler, testMatchID, time.Now().UTC())
+	if err != nil || len(reservations.Reservations) != 0 {
+		t.Errorf("reservations after joining = %v, %v", reservations, err)
+	}
+	d.Terminate(0)
+	if d.NK.StorageObject(seatReservationCollection, testMatchID, "") != nil {
+		t.Error("reservations kept after the match closed")
+	}
+}
+
+func TestMatchJoinAttemptReservationsInProgress(t *testing.T) {
+	d := newTestMatch(t, map[string]interface{}{})
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	tokens := map[string]string{}
+	for _, userID := range []string{"alice", "bob"} {
+		token, err := reserveSeat(d.Ctx, d.NK, marshaler, unmarshaler, testMatchID, userID, minSeats, 0, time.Now().UTC())
+		if err != nil || token == "" {
+			t.Fatalf("no seat reserved for %v: %v", userID, err)
+		}
+		tokens[userID] = token
+	}
+
+	// A holder still joining takes up one seat, not two, so the other holder gets in before either has joined.
+	for _, userID := range []string{"alice", "bob"} {
+		if ok, reason := d.JoinAttempt(matchtest.NewPresence(userID), map[string]string{"reservation": tokens[userID]}); !ok {
+			t.Errorf("%v's join attempt rejected: %v", userID, reason)
+		}
+	}
+	if ok, reason := d.JoinAttempt(matchtest.NewPresence("mallory"), nil); ok || reason != "match full" {
+		t.Errorf("join attempt without a reservation = (%v, %q), want match full", ok, reason)
+	}
+}
+
+func TestFindOrCreatePoolMatch(t *testing.T) {
+	ctx := context.Background()
+	nk := matchtest.NewNakamaModule()
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	start := time.Unix(1600000000, 0)
+	poolKey := matchPoolKey(moduleName, true, defaultBoardSize, defaultBoardSize, 0, standardVariant, minSeats, "")
+
+	tests := []struct {
+		name   string
+		userID string
+		rating int
+		window int
+		after  time.Duration
+		// The match the user created earlier in their search, and a match that has since closed, by the order they
+		// were created in.
+		ownMatch int
+		gone     int
+		// The match the user should be sent to, by the order it was created in.
+		match int
+		own   bool
+	}{
+		{name: "first player creates a match", userID: "alice", rating: 1500, window: 100, match: 1, own: true},
+		{name: "next player joins it", userID: "bob", rating: 1550, window: 100, match: 1},
+		{name: "full match replaced", userID: "carol", rating: 1500, window: 100, match: 2, own: true},
+		{name: "rating outside the window", userID: "dave", rating: 1800, window: 100, match: 3, own: true},
+		{name: "creator searching again keeps their match", userID: "dave", rating: 1800, window: 100, ownMatch: 3, match: 3, own: true},
+		{name: "any rating", userID: "erin", rating: 1200, window: 0, match: 3},
+		{name: "creator's match closed", userID: "carol", rating: 1500, window: 100, ownMatch: 2, gone: 2, after: (matchPoolExpirySec + 1) * time.Second, match: 4, own: true},
+		{name: "old match forgotten", userID: "frank", rating: 1800, window: 100, after: 2 * (matchPoolExpirySec + 1) * time.Second, match: 5, own: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ownMatchID string
+			if tt.ownMatch > 0 {
+				ownMatchID = nk.Created[tt.ownMatch-1].MatchID
+			}
+			if tt.gone > 0 {
+				delete(nk.Matches, nk.Created[tt.gone-1].MatchID)
+			}
+			matchID, token, own, err := findOrCreatePoolMatch(ctx, nk, marshaler, unmarshaler, poolKey, moduleName, map[string]interface{}{}, tt.userID, ownMatchID, minSeats, tt.rating, tt.window, start.Add(tt.after))
+			if err != nil {
+				t.Fatalf("find or create pool match error: %v", err)
+			}
+			if len(nk.Created) < tt.match || matchID != nk.Created[tt.match-1].MatchID || own != tt.own {
+				t.Errorf("match = (%v, own %v), want match %d own %v", matchID, own, tt.match, tt.own)
+			}
+			if token == "" {
+				t.Error("no seat reserved")
+			}
+		})
+	}
+}
+
+func TestRpcFindMatchReservesSeat(t *testing.T) {
+	nk := matchtest.NewNakamaModule()
+
+	// Alice creates a match, which Bob and Carol find while Alice still hasn't joined it.
+	matchID, alice := findTestMatch(t, nk, "alice", `{"fast":true}`)
+	d := matchtest.NewDriver(newTestHandler(), matchID, nk, matchtest.NewLogger(t.Logf))
+	if !d.Init(nk.Created[0].Params) {
+		t.Fatalf("match init failed with params %v", nk.Created[0].Params)
+	}
+	bobMatchID, bob := findTestMatch(t, nk, "bob", `{"fast":true}`)
+	carolMatchID, carol := findTestMatch(t, nk, "carol", `{"fast":true}`)
+	if bobMatchID != matchID || carolMatchID == matchID {
+		t.Fatalf("bob sent to %v and carol to %v, want only bob in alice's match %v", bobMatchID, carolMatchID, matchID)
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		metadata map[string]string
+		accepted bool
+		reason   string
+	}{
+		{name: "reservation for another match", userID: "carol", metadata: map[string]string{"reservation": carol}, accepted: false, reason: "reservation expired"},
+		{name: "dave without a search", userID: "dave", accepted: false, reason: "match full"},
+		{name: "alice", userID: "alice", metadata: map[string]string{"reservation": alice}, accepted: true},
+		{name: "bob", userID: "bob", metadata: map[string]string{"reservation": bob}, accepted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepted, reason := d.Join(matchtest.NewPresence(tt.userID), tt.metadata)
+			if accepted != tt.accepted || reason != tt.reason {
+				t.Errorf("join = (%v, %q), want (%v, %q)", accepted, reason, tt.accepted, tt.reason)
+			}
+		})
+	}
+
+	reservations, _, err := readSeatReservations(d.Ctx, nk, &jsonpb.Unmarshaler{}, matchID, time.Now().UTC())
+	if err != nil || len(reservations.Reservations) != 0 {
+		t.Errorf("reservations after joining = %v, %v", reservations, err)
+	}
+	if !d.StepUntil(delayBetweenGamesSec*tickRate, func() bool { return d.Dispatcher.Last(int64(api.OpCode_OPCODE_START)) != nil }) {
+		t.Error("game did not start once both players joined")
+	}
+}
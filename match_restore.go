@@ -0,0 +1,265 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// System-owned state of matches interrupted by a server shutdown, keyed by the interrupted match ID.
+	savedMatchCollection = "saved_match"
+
+	notificationCodeMatchRestored = 204
+
+	// How long players of a match restored between games have to rejoin it, before the others carry on without them.
+	restoreRejoinSec = 60
+
+	savedMatchListLimit = 100
+)
+
+// Save the state of a match as the server shuts down. Only games in progress, or series part way through, are worth
+// coming back to.
+func (m *MatchHandler) saveMatch(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, s *MatchState, tick int64) {
+	if !s.playing && (s.seriesLength == 0 || s.gamesPlayed == 0 || s.seriesWinner != "") {
+		return
+	}
+
+	matchID, _ := ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	saved := &api.SavedMatch{
+		MatchId:         matchID,
+		Fast:            s.label.Fast == 1,
+		BoardSize:       int32(s.boardSize),
+		WinLength:       int32(s.winLength),
+		BotDifficulty:   s.botDifficulty,
+		SeriesLength:    int32(s.seriesLength),
+		TimeControl:     s.label.TimeControl,
+		FirstMovePolicy: s.firstMovePolicy,
+		Ultimate:        s.ultimate,
+		Variant:         s.variant,
+		Seats:           int32(s.seats),
+
+		ReconnectWindowSec: int32(s.reconnectWindowTicks / tickRate),
+		MaxSpectators:      int32(s.maxSpectators),
+		BotWaitSec:         int32(s.botWaitTicks / tickRate),
+		Rating:             int32(s.label.Rating),
+		PrivateCode:        s.privateCode,
+
+		Playing:                s.playing,
+		Board:                  s.board,
+		Marks:                  s.marks,
+		Mark:                   s.mark,
+		DeadlineRemainingTicks: s.deadlineRemainingTicks,
+		Clocks:                 s.clocks,
+		SeriesScore:            s.seriesScore,
+		GamesPlayed:            int32(s.gamesPlayed),
+		Winner:                 s.winner,
+		Replay:                 s.replay,
+		GameTicks:              tick - s.gameStartTick,
+		ActiveSubBoard:         s.activeSubBoard,
+		Eliminated:             s.eliminatedUserIDs(),
+		Rated:                  s.rated,
+	}
+
+	var buf bytes.Buffer
+	if err := m.marshaler.Marshal(&buf, saved); err != nil {
+		logger.Error("error encoding saved match: %v", err)
+		return
+	}
+	if _, err := nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+		Collection:      savedMatchCollection,
+		Key:             matchID,
+		Value:           buf.String(),
+		PermissionRead:  0, // No client read.
+		PermissionWrite: 0, // No client write.
+	}}); err != nil {
+		logger.Error("error writing saved match: %v", err)
+	}
+}
+
+// Pick up a saved match where it left off. Its players start out disconnected, with their seats held for them.
+func (s *MatchState) restore(saved *api.SavedMatch) {
+	s.playing = saved.Playing
+	s.board = saved.Board
+	s.marks = saved.Marks
+	s.mark = saved.Mark
+	s.winner = saved.Winner
+	s.deadlineRemainingTicks = saved.DeadlineRemainingTicks
+	s.clocks = saved.Clocks
+	s.gamesPlayed = int(saved.GamesPlayed)
+	s.replay = saved.Replay
+	s.gameStartTick = -saved.GameTicks
+	s.botMoveRemainingTicks = botMoveDelaySec * tickRate
+	s.rated = saved.Rated
+	s.eliminated = make(map[string]bool, s.seats)
+	for _, userID := range saved.Eliminated {
+		s.eliminated[userID] = true
+	}
+	if saved.SeriesScore != nil {
+		s.seriesScore = saved.SeriesScore
+	}
+	if s.ultimate {
+		s.restoreSubBoardWinners()
+		s.activeSubBoard = saved.ActiveSubBoard
+	}
+
+	for userID := range saved.Marks {
+		if userID == botUserID {
+			s.presences[botUserID] = &botPresence{}
+			continue
+		}
+		s.presences[userID] = nil
+		if s.playing && s.reconnectWindowTicks > 0 && !s.eliminated[userID] {
+			s.reconnectRemainingTicks[userID] = s.reconnectWindowTicks
+		}
+	}
+	if !s.playing {
+		s.restoreRemainingTicks = restoreRejoinSec * tickRate
+	}
+}
+
+// Whether the match was restored between games and is still waiting for its players to rejoin. Until they all have,
+// or their time runs out, nobody is taken for having walked away from the series.
+func (s *MatchState) awaitingRestoredPlayers() bool {
+	if s.restoreRemainingTicks == 0 {
+		return false
+	}
+	s.restoreRemainingTicks--
+	for _, presence := range s.presences {
+		if presence == nil {
+			return s.restoreRemainingTicks > 0
+		}
+	}
+	s.restoreRemainingTicks = 0
+	return false
+}
+
+// Recreate the matches saved when the server last shut down, and let their players know where to rejoin.
+func restoreMatches(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler) {
+	cursor := ""
+	for {
+		objects, next, err := nk.StorageList(ctx, "", savedMatchCollection, savedMatchListLimit, cursor)
+		if err != nil {
+			logger.Error("error listing saved matches: %v", err)
+			return
+		}
+
+		for _, object := range objects {
+			saved := &api.SavedMatch{}
+			if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(object.GetValue())), saved); err != nil {
+				logger.Error("error decoding saved match %v: %v", object.GetKey(), err)
+				continue
+			}
+
+			players := make([]string, 0, len(saved.Marks))
+			for userID := range saved.Marks {
+				if userID != botUserID {
+					players = append(players, userID)
+				}
+			}
+
+			// The new match is reserved for the same players, so nobody else takes a seat while they find their way back.
+			params := map[string]interface{}{
+				"fast":                 saved.Fast,
+				"bot_difficulty":       int(saved.BotDifficulty),
+				"series_length":        int(saved.SeriesLength),
+				"time_control":         saved.TimeControl,
+				"first_move_policy":    int(saved.FirstMovePolicy),
+				"variant":              saved.Variant,
+				"seats":                nonZeroParam(saved.Seats),
+				"reconnect_window_sec": int(saved.ReconnectWindowSec),
+				"max_spectators":       int(saved.MaxSpectators),
+				"bot_wait_sec":         int(saved.BotWaitSec),
+				"rating":               int(saved.Rating),
+				"reserved_user_ids":    players,
+				"restore":              object.GetValue(),
+			}
+			module := ultimateModuleName
+			if !saved.Ultimate {
+				module = moduleName
+				params["board_size"] = int(saved.BoardSize)
+				params["win_length"] = int(saved.WinLength)
+			}
+
+			// The invite code was released when the match shut down, take it back unless another match has since.
+			var code *api.PrivateMatchCode
+			var codeVersion string
+			if saved.PrivateCode != "" {
+				code = &api.PrivateMatchCode{
+					ExpireTime: time.Now().UTC().Add(privateCodeExpirySec * time.Second).Unix(),
+				}
+				if codeVersion, err = writePrivateCode(ctx, nk, marshaler, saved.PrivateCode, "*", code); err != nil {
+					logger.Warn("invite code %v of match %v not restored: %v", saved.PrivateCode, saved.MatchId, err)
+					code = nil
+				} else {
+					params["private_code"] = saved.PrivateCode
+				}
+			}
+
+			matchID, err := nk.MatchCreate(ctx, module, params)
+			if err != nil {
+				logger.Error("error restoring match %v: %v", saved.MatchId, err)
+				if code != nil {
+					deletePrivateCode(ctx, logger, nk, saved.PrivateCode, codeVersion)
+				}
+				continue
+			}
+			logger.Info("restored match %v as %v", saved.MatchId, matchID)
+
+			if code != nil {
+				code.MatchId = matchID
+				if _, err := writePrivateCode(ctx, nk, marshaler, saved.PrivateCode, codeVersion, code); err != nil {
+					logger.Error("error writing invite code: %v", err)
+				}
+			}
+
+			notifications := make([]*runtime.NotificationSend, 0, len(players))
+			for _, userID := range players {
+				notifications = append(notifications, &runtime.NotificationSend{
+					Code: notificationCodeMatchRestored,
+					Content: map[string]interface{}{
+						"match_id":          matchID,
+						"previous_match_id": saved.MatchId,
+					},
+					Persistent: true,
+					Sender:     "", // Server sent.
+					Subject:    "Your match is ready to rejoin.",
+					UserID:     userID,
+				})
+			}
+			if err := nk.NotificationsSend(ctx, notifications); err != nil {
+				logger.Error("error notifying players of restored match: %v", err)
+			}
+
+			if err := nk.StorageDelete(ctx, []*runtime.StorageDelete{{
+				Collection: savedMatchCollection,
+				Key:        object.GetKey(),
+			}}); err != nil {
+				logger.Error("error deleting saved match: %v", err)
+			}
+		}
+
+		if next == "" {
+			return
+		}
+		cursor = next
+	}
+}
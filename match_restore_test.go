@@ -0,0 +1,198 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestMatchSaveAndRestore(t *testing.T) {
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+
+	tests := []struct {
+		name string
+		// Whether another match takes the invite code while the server is down.
+		codeTaken bool
+	}{
+		{name: "private match"},
+		{name: "invite code taken meanwhile", codeTaken: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			nk := matchtest.NewNakamaModule()
+			code := &api.PrivateMatchCode{MatchId: "match-a.node", ExpireTime: time.Now().Add(time.Minute).Unix()}
+			if _, err := writePrivateCode(ctx, nk, marshaler, "ABC234", "*", code); err != nil {
+				t.Fatal(err)
+			}
+
+			d := matchtest.NewDriver(newTestHandler(), "match-a.node", nk, matchtest.NewLogger(t.Logf))
+			if !d.Init(map[string]interface{}{
+				"fast":                 true,
+				"reconnect_window_sec": 45,
+				"max_spectators":       3,
+				"bot_wait_sec":         20,
+				"rating":               1720,
+				"private_code":         "ABC234",
+			}) {
+				t.Fatal("match init failed")
+			}
+			x, _ := startTestGame(t, d)
+			d.Step(moveMessage(x, 4))
+			before := d.State.(*MatchState)
+			d.Terminate(0)
+
+			if tt.codeTaken {
+				code.MatchId = "match-b.node"
+				if _, err := writePrivateCode(ctx, nk, marshaler, "ABC234", "*", code); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			restoreMatches(ctx, matchtest.NewLogger(t.Logf), nk, marshaler, unmarshaler)
+			if len(nk.Created) != 1 {
+				t.Fatalf("restored %d matches, want 1", len(nk.Created))
+			}
+			if nk.StorageObject(savedMatchCollection, "match-a.node", "") != nil {
+				t.Error("saved match kept after restoring it")
+			}
+			if len(nk.Notifications) != 2 {
+				t.Errorf("notifications = %v, want both players told where to rejoin", nk.Notifications)
+			}
+
+			restored := matchtest.NewDriver(newTestHandler(), nk.Created[0].MatchID, nk, matchtest.NewLogger(t.Logf))
+			if !restored.Init(nk.Created[0].Params) {
+				t.Fatalf("restored match init failed with params %v", nk.Created[0].Params)
+			}
+			s := restored.State.(*MatchState)
+			if !s.playing || !reflect.DeepEqual(s.board, before.board) || !reflect.DeepEqual(s.marks, before.marks) || s.mark != before.mark {
+				t.Errorf("restored game = (playing %v, board %v, marks %v, mark %v), want the game in progress", s.playing, s.board, s.marks, s.mark)
+			}
+			if s.reconnectWindowTicks != 45*tickRate || s.maxSpectators != 3 || s.botWaitTicks != 20*tickRate {
+				t.Errorf("restored settings = (reconnect %v, spectators %v, bot wait %v)", s.reconnectWindowTicks, s.maxSpectators, s.botWaitTicks)
+			}
+			label := &MatchLabel{}
+			decodeTestLabel(t, restored.Label, label)
+			if label.Rating != 1720 || label.Private != 1 || label.Reserved != 1 {
+				t.Errorf("restored label = %+v", label)
+			}
+
+			// The invite code leads to the restored match, unless it went to another match first.
+			wantCode, wantMatchID := "ABC234", nk.Created[0].MatchID
+			if tt.codeTaken {
+				wantCode, wantMatchID = "", "match-b.node"
+			}
+			if s.privateCode != wantCode {
+				t.Errorf("restored invite code = %q, want %q", s.privateCode, wantCode)
+			}
+			stored := &api.PrivateMatchCode{}
+			if object := nk.StorageObject(privateCodeCollection, "ABC234", ""); object == nil {
+				t.Error("invite code not stored")
+			} else if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(object.GetValue())), stored); err != nil || stored.MatchId != wantMatchID {
+				t.Errorf("invite code leads to %v, want %v", stored.MatchId, wantMatchID)
+			}
+		})
+	}
+}
+
+func TestMatchSaveAndRestoreSeriesBetweenGames(t *testing.T) {
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+
+	tests := []struct {
+		name string
+		bot  bool
+		// Players who rejoin the restored match.
+		returning []string
+		// The player the series should go to, if it ends before another game starts.
+		seriesWinner string
+	}{
+		{name: "players return", returning: []string{"alice", "bob"}},
+		{name: "player returns to the bot", bot: true, returning: []string{"alice"}},
+		{name: "opponent never returns", returning: []string{"alice"}, seriesWinner: "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			nk := matchtest.NewNakamaModule()
+			params := map[string]interface{}{"fast": true, "series_length": 3}
+			if tt.bot {
+				params["bot_difficulty"] = int(api.BotDifficulty_BOT_DIFFICULTY_RANDOM)
+				params["bot_wait_sec"] = 1
+			}
+			d := matchtest.NewDriver(newTestHandler(), "match-a.node", nk, matchtest.NewLogger(t.Logf))
+			if !d.Init(params) {
+				t.Fatal("match init failed")
+			}
+
+			// Finish the first game of the series, by a timeout against the bot.
+			if tt.bot {
+				if ok, reason := d.Join(matchtest.NewPresence("alice"), nil); !ok {
+					t.Fatalf("alice could not join: %v", reason)
+				}
+			} else {
+				startTestGame(t, d)
+			}
+			if !d.StepUntil(4*turnTimeFastSec*tickRate, func() bool { return d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)) != nil }) {
+				t.Fatal("first game did not end")
+			}
+			d.Terminate(0)
+
+			restoreMatches(ctx, matchtest.NewLogger(t.Logf), nk, marshaler, unmarshaler)
+			if len(nk.Created) != 1 {
+				t.Fatalf("restored %d matches, want 1", len(nk.Created))
+			}
+			restored := matchtest.NewDriver(newTestHandler(), nk.Created[0].MatchID, nk, matchtest.NewLogger(t.Logf))
+			if !restored.Init(nk.Created[0].Params) {
+				t.Fatalf("restored match init failed with params %v", nk.Created[0].Params)
+			}
+
+			// Nobody concedes the series before they've had a chance to come back.
+			restored.Steps(tickRate)
+			if restored.Dispatcher.Last(int64(api.OpCode_OPCODE_SERIES_DONE)) != nil {
+				t.Fatal("series ended before the players could rejoin")
+			}
+			for _, userID := range tt.returning {
+				if ok, reason := restored.Join(matchtest.NewPresence(userID), nil); !ok {
+					t.Fatalf("%v could not rejoin: %v", userID, reason)
+				}
+			}
+
+			restored.StepUntil((restoreRejoinSec+delayBetweenGamesSec)*tickRate, func() bool {
+				return restored.Dispatcher.Last(int64(api.OpCode_OPCODE_START)) != nil || restored.Dispatcher.Last(int64(api.OpCode_OPCODE_SERIES_DONE)) != nil
+			})
+			if tt.seriesWinner == "" {
+				if restored.Dispatcher.Last(int64(api.OpCode_OPCODE_START)) == nil {
+					t.Error("next game of the series did not start")
+				}
+				return
+			}
+			done := &api.SeriesDone{}
+			decodeTestMessage(t, restored.Dispatcher.Last(int64(api.OpCode_OPCODE_SERIES_DONE)), done)
+			if done.Winner != tt.seriesWinner {
+				t.Errorf("series winner = %v, want %v", done.Winner, tt.seriesWinner)
+			}
+		})
+	}
+}
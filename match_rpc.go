@@ -63,8 +63,9 @@ func rpcFindMatch(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler)
 		logger.Debug("New session with %d expiry time: %v", exp, token)
 
 		var resp struct {
-			Session  string   `json:"token"`
-			MatchIds []string `protobuf:"bytes,1,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
+			Session     string   `json:"token"`
+			MatchIds    []string `protobuf:"bytes,1,rep,name=match_ids,json=matchIds,proto3" json:"match_ids,omitempty"`
+			Reservation string   `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
 		}
 		resp.Session = token
 
@@ -73,35 +74,140 @@ func rpcFindMatch(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler)
 			return "", errUnmarshal
 		}
 
-		maxSize := 1
+		seats := int(request.Seats)
+		if seats == 0 {
+			seats = minSeats
+		}
+		if seats < minSeats || seats > maxSeats {
+			return "", errBadInput
+		}
+
+		boardSize, winLength, ok := boardParams(map[string]interface{}{
+			"board_size": nonZeroParam(request.BoardSize),
+			"win_length": nonZeroParam(request.WinLength),
+		}, seats)
+		if !ok {
+			return "", errBadInput
+		}
+
+		variant := request.Variant
+		if variant == "" {
+			variant = standardVariant
+		}
+		if _, ok := variants[variant]; !ok {
+			return "", errBadInput
+		}
+
+		// Only the standard game is played with more than two seats, and only by people.
+		if seats > minSeats && (request.Ultimate || variant != standardVariant || request.BotDifficulty != api.BotDifficulty_BOT_DIFFICULTY_UNSPECIFIED || request.SeriesLength != 0) {
+			return "", errBadInput
+		}
+
+		// Ultimate tic-tac-toe is always played on the same board.
+		module := moduleName
+		var ultimate int
+		if request.Ultimate {
+			if request.BoardSize != 0 || request.WinLength != 0 || variant != standardVariant {
+				return "", errBadInput
+			}
+			module = ultimateModuleName
+			ultimate = 1
+			boardSize, winLength = ultimateBoardSize, ultimateSubBoardSize
+		}
+		if !validSeriesLength(int(request.SeriesLength)) {
+			return "", errBadInput
+		}
+		if _, ok := timeControls[request.TimeControl]; request.TimeControl != "" && !ok {
+			return "", errBadInput
+		}
+
+		// Matches with a seat left for the user.
+		maxSize := seats - 1
 		var fast int
 		if request.Fast {
 			fast = 1
 		}
-		query := fmt.Sprintf("+label.open:1 +label.fast:%d", fast)
 
-		matchIDs := make([]string, 0, 10)
+		// Look for opponents of similar skill, accepting a wider range of ratings the longer the user has been searching
+		// for the same kind of game.
+		rating, err := playerRating(ctx, nk, unmarshaler, userID, ratingKey(request.Fast))
+		if err != nil {
+			logger.Error("error reading rating: %v", err)
+			return "", errInternalError
+		}
+		poolKey := matchPoolKey(module, request.Fast, boardSize, winLength, int(request.SeriesLength), variant, seats, request.TimeControl)
+		t := time.Now().UTC()
+		search, wait, err := matchSearchWait(ctx, nk, marshaler, unmarshaler, userID, poolKey, t)
+		if err != nil {
+			logger.Error("error recording match search: %v", err)
+			return "", errInternalError
+		}
+		window := ratingWindow(wait)
+
+		query := fmt.Sprintf("+label.open:1 +label.fast:%d +label.ultimate:%d +label.board_size:%d +label.win_length:%d +label.series_length:%d +label.variant:%v +label.seats:%d %v %v", fast, ultimate, boardSize, winLength, request.SeriesLength, variant, seats, timeControlQuery(request.TimeControl), ratingQuery(labelRating(rating), window))
+
 		matches, err := nk.MatchList(ctx, 10, true, "", nil, &maxSize, query)
 		if err != nil {
 			logger.Error("error listing matches: %v", err)
 			return "", errInternalError
 		}
-		if len(matches) > 0 {
-			// There are one or more ongoing matches the user could join.
-			for _, match := range matches {
-				matchIDs = append(matchIDs, match.MatchId)
+
+		// Hold a seat in the first ongoing match that still has one once the seats held for others are counted, so
+		// players searching at the same time aren't sent to the same last seat.
+		var matchID, reservation string
+		for _, match := range matches {
+			// The user's own match is where they wait for an opponent, not one to be paired in.
+			if match.MatchId == search.MatchId {
+				continue
+			}
+			if reservation, err = reserveSeat(ctx, nk, marshaler, unmarshaler, match.MatchId, userID, seats, int(match.Size), t); err != nil {
+				logger.Error("error reserving seat: %v", err)
+				continue
 			}
-		} else {
+			if reservation != "" {
+				matchID = match.MatchId
+				break
+			}
+		}
+
+		own := false
+		if matchID == "" {
 			// No available matches found, create a new one.
-			matchID, err := nk.MatchCreate(ctx, moduleName, map[string]interface{}{"fast": request.Fast})
+			params := map[string]interface{}{
+				"fast":           request.Fast,
+				"bot_difficulty": int(request.BotDifficulty),
+				"series_length":  int(request.SeriesLength),
+				"time_control":   request.TimeControl,
+				"variant":        variant,
+				"seats":          seats,
+				"rating":         labelRating(rating),
+			}
+			if !request.Ultimate {
+				params["board_size"] = boardSize
+				params["win_length"] = winLength
+			}
+			matchID, reservation, own, err = findOrCreatePoolMatch(ctx, nk, marshaler, unmarshaler, poolKey, module, params, userID, search.MatchId, seats, labelRating(rating), window, t)
 			if err != nil {
 				logger.Error("error creating match: %v", err)
 				return "", errInternalError
 			}
-			matchIDs = append(matchIDs, matchID)
 		}
 
-		resp.MatchIds = matchIDs
+		// The search goes on from the user's own match, widening the range of ratings, until they're paired with
+		// someone else.
+		if own {
+			if search.MatchId != matchID {
+				search.MatchId = matchID
+				if err := writeMatchSearch(ctx, nk, marshaler, userID, poolKey, search); err != nil {
+					logger.Error("error recording match search: %v", err)
+				}
+			}
+		} else if err := endMatchSearch(ctx, nk, userID, poolKey); err != nil {
+			logger.Error("error ending match search: %v", err)
+		}
+
+		resp.MatchIds = []string{matchID}
+		resp.Reservation = reservation
 
 		out, err := json.Marshal(resp)
 		if err != nil {
@@ -114,6 +220,14 @@ func rpcFindMatch(marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler)
 	}
 }
 
+// Proto3 can't tell an unset number from zero, so treat zero as not provided and let the match defaults apply.
+func nonZeroParam(v int32) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return int(v)
+}
+
 // TODO: implement rpc function that returns the game state, precences and current tick, like the console does
 // func (s *ConsoleServer) GetMatchState(ctx context.Context, in *console.MatchStateRequest) (*console.MatchState, error) {
 // 	// Validate the match ID.
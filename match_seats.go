@@ -0,0 +1,111 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	minSeats = 2
+	maxSeats = 4
+
+	// Matches with more than two seats need a larger board to leave everyone room to play.
+	multiSeatMinBoardSize     = 5
+	multiSeatDefaultBoardSize = 6
+	multiSeatDefaultWinLength = 4
+)
+
+// The marks handed out to players, in turn order.
+var seatMarks = []api.Mark{api.Mark_MARK_X, api.Mark_MARK_O, api.Mark_MARK_TRIANGLE, api.Mark_MARK_SQUARE}
+
+// The mark to play after the current one, skipping players who have been eliminated.
+func (s *MatchState) nextMark() api.Mark {
+	holders := make(map[api.Mark]string, len(s.marks))
+	for userID, mark := range s.marks {
+		holders[mark] = userID
+	}
+	marks := seatMarks[:len(s.marks)]
+
+	current := 0
+	for i, mark := range marks {
+		if mark == s.mark {
+			current = i
+		}
+	}
+	for i := 1; i <= len(marks); i++ {
+		mark := marks[(current+i)%len(marks)]
+		if !s.eliminated[holders[mark]] {
+			return mark
+		}
+	}
+	return s.mark
+}
+
+// Take a player out of the game in progress. With two seats their opponent wins, otherwise the game goes on without
+// them until only one player is left.
+func (m *MatchHandler) forfeit(ctx context.Context, logger runtime.Logger, nk runtime.NakamaModule, dispatcher runtime.MatchDispatcher, s *MatchState, t time.Time, userID string, winnerPositions []int32, reason api.DoneReason) {
+	mark := s.marks[userID]
+	if s.seats <= minSeats {
+		m.endGame(ctx, logger, nk, dispatcher, s, t, opponentMark(mark), winnerPositions, reason)
+		return
+	}
+
+	s.eliminated[userID] = true
+	delete(s.reconnectRemainingTicks, userID)
+	remaining := make([]string, 0, len(s.marks))
+	for userID := range s.marks {
+		if !s.eliminated[userID] {
+			remaining = append(remaining, userID)
+		}
+	}
+	if len(remaining) == 1 {
+		m.endGame(ctx, logger, nk, dispatcher, s, t, s.marks[remaining[0]], nil, reason)
+		return
+	}
+
+	// Play passes on if it was the eliminated player's turn, with whatever they had left banked.
+	if s.mark == mark {
+		if s.clocks != nil {
+			s.clocks[userID] = s.deadlineRemainingTicks
+		}
+		s.mark = s.nextMark()
+		s.deadlineRemainingTicks = s.turnTicks()
+	}
+
+	m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_PLAYER_ELIMINATED, &api.PlayerEliminated{
+		UserId:   userID,
+		Mark:     mark,
+		Reason:   reason,
+		NextMark: s.mark,
+		Deadline: t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix(),
+		Sequence: s.nextSequence(),
+	}, nil)
+}
+
+// The user IDs of players eliminated from the game in progress, in a stable order.
+func (s *MatchState) eliminatedUserIDs() []string {
+	userIDs := make([]string, 0, len(s.eliminated))
+	for userID := range s.eliminated {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	return userIDs
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestMatchSeatsRoundRobin(t *testing.T) {
+	d := newTestMatch(t, map[string]interface{}{"seats": 3})
+	players := startTestGameWith(t, d, "alice", "bob", "carol")
+	if testState(d).boardSize != multiSeatDefaultBoardSize || testState(d).winLength != multiSeatDefaultWinLength {
+		t.Fatalf("board = %dx%d win %d, want the multi-seat defaults", testState(d).boardSize, testState(d).boardSize, testState(d).winLength)
+	}
+
+	// Triangle completes the third row while X and O scatter their marks.
+	turns := []api.Mark{api.Mark_MARK_X, api.Mark_MARK_O, api.Mark_MARK_TRIANGLE}
+	moves := []int32{0, 6, 12, 2, 8, 13, 4, 10, 14, 30, 33, 15}
+	for i, position := range moves {
+		d.Step(moveMessage(players[turns[i%3]], position))
+	}
+	if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) > 0 {
+		t.Fatalf("%d moves rejected", len(rejected))
+	}
+
+	msg := &api.Done{}
+	decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)), msg)
+	if msg.Winner != api.Mark_MARK_TRIANGLE || msg.Reason != api.DoneReason_DONE_REASON_WIN_LINE {
+		t.Errorf("done = (%v, %v), want (%v, %v)", msg.Winner, msg.Reason, api.Mark_MARK_TRIANGLE, api.DoneReason_DONE_REASON_WIN_LINE)
+	}
+}
+
+func TestMatchSeatsElimination(t *testing.T) {
+	tests := []struct {
+		name string
+		// Takes the player holding O out of the game, while it's X's turn.
+		eliminate func(d *matchtest.Driver, players map[api.Mark]*matchtest.Presence)
+		reason    api.DoneReason
+	}{
+		{
+			name: "leaving",
+			eliminate: func(d *matchtest.Driver, players map[api.Mark]*matchtest.Presence) {
+				d.Leave(players[api.Mark_MARK_O])
+			},
+			reason: api.DoneReason_DONE_REASON_OPPONENT_LEFT,
+		},
+		{
+			name: "resigning",
+			eliminate: func(d *matchtest.Driver, players map[api.Mark]*matchtest.Presence) {
+				d.Step(matchtest.NewMessage(players[api.Mark_MARK_O], int64(api.OpCode_OPCODE_RESIGN), ""))
+			},
+			reason: api.DoneReason_DONE_REASON_RESIGN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{"seats": 3})
+			players := startTestGameWith(t, d, "alice", "bob", "carol")
+			start := &api.Start{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_START)), start)
+
+			tt.eliminate(d, players)
+			eliminated := &api.PlayerEliminated{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_PLAYER_ELIMINATED)), eliminated)
+			if eliminated.Mark != api.Mark_MARK_O || eliminated.Reason != tt.reason || eliminated.NextMark != api.Mark_MARK_X {
+				t.Errorf("eliminated = %v, want O out with X to move", eliminated)
+			}
+			if eliminated.Sequence <= start.Sequence {
+				t.Errorf("eliminated sequence = %v, want after the start's %v", eliminated.Sequence, start.Sequence)
+			}
+
+			// Play skips the eliminated player, whose marks stay on the board.
+			d.Step(moveMessage(players[api.Mark_MARK_X], 0))
+			if testState(d).mark != api.Mark_MARK_TRIANGLE {
+				t.Errorf("mark to play = %v, want %v", testState(d).mark, api.Mark_MARK_TRIANGLE)
+			}
+			d.Step(moveMessage(players[api.Mark_MARK_O], 1))
+			if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) != 1 {
+				t.Error("eliminated player allowed to move")
+			}
+
+			// The last player left wins.
+			d.Leave(players[api.Mark_MARK_TRIANGLE])
+			msg := &api.Done{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)), msg)
+			if msg.Winner != api.Mark_MARK_X || msg.Reason != api.DoneReason_DONE_REASON_OPPONENT_LEFT {
+				t.Errorf("done = (%v, %v), want (%v, %v)", msg.Winner, msg.Reason, api.Mark_MARK_X, api.DoneReason_DONE_REASON_OPPONENT_LEFT)
+			}
+		})
+	}
+}
+
+func TestMatchSeatsTimeout(t *testing.T) {
+	d := newTestMatch(t, map[string]interface{}{"seats": 4})
+	players := startTestGameWith(t, d, "alice", "bob", "carol", "dave")
+
+	d.Steps(turnTimeFastSec * tickRate)
+	eliminated := &api.PlayerEliminated{}
+	decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_PLAYER_ELIMINATED)), eliminated)
+	if eliminated.UserId != players[api.Mark_MARK_X].UserID || eliminated.Reason != api.DoneReason_DONE_REASON_TIMEOUT {
+		t.Errorf("eliminated = %v, want X timed out", eliminated)
+	}
+	if !testState(d).playing || testState(d).mark != api.Mark_MARK_O {
+		t.Errorf("game over or wrong player to move after a timeout: %v", testState(d).mark)
+	}
+}
+
+func TestMatchSeatsInit(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		ok     bool
+	}{
+		{name: "three seats", params: map[string]interface{}{"seats": 3}, ok: true},
+		{name: "four seats on a large board", params: map[string]interface{}{"seats": 4, "board_size": 8, "win_length": 5}, ok: true},
+		{name: "too many seats", params: map[string]interface{}{"seats": 5}},
+		{name: "too few seats", params: map[string]interface{}{"seats": 1}},
+		{name: "board too small", params: map[string]interface{}{"seats": 3, "board_size": 4}},
+		{name: "bot", params: map[string]interface{}{"seats": 3, "bot_difficulty": 1}},
+		{name: "series", params: map[string]interface{}{"seats": 3, "series_length": 3}},
+		{name: "variant", params: map[string]interface{}{"seats": 3, "variant": "misere"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params["fast"] = true
+			d := matchtest.NewDriver(newTestHandler(), testMatchID, matchtest.NewNakamaModule(), matchtest.NewLogger(nil))
+			if ok := d.Init(tt.params); ok != tt.ok {
+				t.Fatalf("init = %v, want %v", ok, tt.ok)
+			}
+			if !tt.ok {
+				return
+			}
+
+			seats := tt.params["seats"].(int)
+			for i := 0; i < seats; i++ {
+				if ok, reason := d.Join(matchtest.NewPresence(string(rune('a'+i))), nil); !ok {
+					t.Fatalf("player %d rejected: %v", i, reason)
+				}
+			}
+			if ok, reason := d.JoinAttempt(matchtest.NewPresence("late"), nil); ok || reason != "match full" {
+				t.Errorf("join attempt = (%v, %q), want match full", ok, reason)
+			}
+		})
+	}
+}
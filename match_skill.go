@@ -0,0 +1,138 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	// User-owned searches in progress, one object per kind of game keyed like the match pools, so a search for
+	// different settings starts over rather than returning to a match that no longer fits.
+	matchSearchCollection = "match_search"
+	// A search nobody asked about for this long is forgotten, and the next one starts over with the narrowest range.
+	matchSearchExpirySec = 60
+
+	// Opponents start out within this many rating points of the player.
+	initialRatingWindow = 100
+	// The range widens by a step for each period the player has been waiting.
+	ratingWindowStep    = 50
+	ratingWindowStepSec = 5
+	// Past this range the player is offered a match with anyone, so nobody waits forever.
+	maxRatingWindow = 400
+)
+
+// How many rating points away from the player opponents may be after waiting this long. Zero allows any rating.
+func ratingWindow(wait time.Duration) int {
+	window := initialRatingWindow + ratingWindowStep*int(wait/(ratingWindowStepSec*time.Second))
+	if window > maxRatingWindow {
+		return 0
+	}
+	return window
+}
+
+// The match listing query clauses for matches advertising a rating within the window.
+func ratingQuery(rating, window int) string {
+	if window == 0 {
+		return ""
+	}
+	return fmt.Sprintf("+label.rating:>=%d +label.rating:<=%d", rating-window, rating+window)
+}
+
+// Read the player's rating in the given mode, or the starting rating if they haven't played a rated game yet.
+func playerRating(ctx context.Context, nk runtime.NakamaModule, unmarshaler *jsonpb.Unmarshaler, userID, key string) (*api.Rating, error) {
+	objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+		Collection: ratingCollection,
+		Key:        key,
+		UserID:     userID,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	rating := newRating()
+	if len(objects) > 0 {
+		if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(objects[0].GetValue())), rating); err != nil {
+			return nil, err
+		}
+	}
+	return rating, nil
+}
+
+// Record another request in the player's search for a match, starting a new search if there isn't a recent one. Returns
+// the search along with how long they've been searching.
+func matchSearchWait(ctx context.Context, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, unmarshaler *jsonpb.Unmarshaler, userID, key string, t time.Time) (*api.MatchSearch, time.Duration, error) {
+	objects, err := nk.StorageRead(ctx, []*runtime.StorageRead{{
+		Collection: matchSearchCollection,
+		Key:        key,
+		UserID:     userID,
+	}})
+	if err != nil {
+		return nil, 0, err
+	}
+	search := &api.MatchSearch{}
+	if len(objects) > 0 {
+		if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(objects[0].GetValue())), search); err != nil {
+			return nil, 0, err
+		}
+	}
+	if t.Unix()-search.LastTime > matchSearchExpirySec {
+		search = &api.MatchSearch{StartTime: t.Unix()}
+	}
+	search.LastTime = t.Unix()
+
+	if err := writeMatchSearch(ctx, nk, marshaler, userID, key, search); err != nil {
+		return nil, 0, err
+	}
+	return search, time.Duration(search.LastTime-search.StartTime) * time.Second, nil
+}
+
+// Store the player's search for a match.
+func writeMatchSearch(ctx context.Context, nk runtime.NakamaModule, marshaler *jsonpb.Marshaler, userID, key string, search *api.MatchSearch) error {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, search); err != nil {
+		return err
+	}
+	_, err := nk.StorageWrite(ctx, []*runtime.StorageWrite{{
+		Collection:      matchSearchCollection,
+		Key:             key,
+		UserID:          userID,
+		Value:           buf.String(),
+		PermissionRead:  0, // No client read.
+		PermissionWrite: 0, // No client write.
+	}})
+	return err
+}
+
+// Forget the player's search once they've been paired with someone else.
+func endMatchSearch(ctx context.Context, nk runtime.NakamaModule, userID, key string) error {
+	return nk.StorageDelete(ctx, []*runtime.StorageDelete{{
+		Collection: matchSearchCollection,
+		Key:        key,
+		UserID:     userID,
+	}})
+}
+
+// Round a rating to the whole number advertised in match labels.
+func labelRating(rating *api.Rating) int {
+	return int(math.Round(rating.Rating))
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestRatingWindow(t *testing.T) {
+	tests := []struct {
+		wait   time.Duration
+		window int
+		query  string
+	}{
+		{wait: 0, window: initialRatingWindow, query: "+label.rating:>=1400 +label.rating:<=1600"},
+		{wait: 4 * time.Second, window: initialRatingWindow, query: "+label.rating:>=1400 +label.rating:<=1600"},
+		{wait: 12 * time.Second, window: initialRatingWindow + 2*ratingWindowStep, query: "+label.rating:>=1300 +label.rating:<=1700"},
+		{wait: 30 * time.Second, window: maxRatingWindow, query: "+label.rating:>=1100 +label.rating:<=1900"},
+		{wait: 35 * time.Second, window: 0, query: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wait.String(), func(t *testing.T) {
+			window := ratingWindow(tt.wait)
+			if window != tt.window {
+				t.Errorf("window = %v, want %v", window, tt.window)
+			}
+			if query := ratingQuery(defaultRating, window); query != tt.query {
+				t.Errorf("query = %q, want %q", query, tt.query)
+			}
+		})
+	}
+}
+
+func TestMatchSearchWait(t *testing.T) {
+	ctx := context.Background()
+	nk := matchtest.NewNakamaModule()
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	start := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name  string
+		after time.Duration
+		// End the search before this request.
+		end  bool
+		wait time.Duration
+	}{
+		{name: "first request", wait: 0},
+		{name: "request soon after", after: 20 * time.Second, wait: 20 * time.Second},
+		{name: "requests keep the search going", after: 70 * time.Second, wait: 70 * time.Second},
+		{name: "search left alone", after: 140 * time.Second, wait: 0},
+		{name: "search ended", after: 150 * time.Second, end: true, wait: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.end {
+				if err := endMatchSearch(ctx, nk, "alice", ratingKeyFast); err != nil {
+					t.Fatalf("end match search error: %v", err)
+				}
+			}
+			search, wait, err := matchSearchWait(ctx, nk, marshaler, unmarshaler, "alice", ratingKeyFast, start.Add(tt.after))
+			if err != nil {
+				t.Fatalf("match search wait error: %v", err)
+			}
+			if wait != tt.wait {
+				t.Errorf("wait = %v, want %v", wait, tt.wait)
+			}
+			if wait == 0 && search.MatchId != "" {
+				t.Errorf("new search still waiting in match %v", search.MatchId)
+			}
+
+			// Remember a match to wait in, which only a search that starts over forgets.
+			search.MatchId = testMatchID
+			if err := writeMatchSearch(ctx, nk, marshaler, "alice", ratingKeyFast, search); err != nil {
+				t.Fatalf("write match search error: %v", err)
+			}
+		})
+	}
+
+	if nk.StorageObject(matchSearchCollection, ratingKeyNormal, "alice") != nil {
+		t.Error("fast search recorded as a normal speed search")
+	}
+}
+
+func TestMatchLabelRating(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		rating int
+	}{
+		{name: "starting rating", params: map[string]interface{}{}, rating: defaultRating},
+		{name: "rating from params", params: map[string]interface{}{"rating": 1720}, rating: 1720},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, tt.params)
+			label := &MatchLabel{}
+			decodeTestLabel(t, d.Label, label)
+			if label.Rating != tt.rating {
+				t.Errorf("label rating = %v, want %v", label.Rating, tt.rating)
+			}
+		})
+	}
+}
+
+func TestRpcFindMatchWidensRatingWindow(t *testing.T) {
+	nk := matchtest.NewNakamaModule()
+	marshaler, unmarshaler := &jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{}
+	rating := newRating()
+	rating.Rating = 1800
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, rating); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nk.StorageWrite(context.Background(), []*runtime.StorageWrite{{Collection: ratingCollection, Key: ratingKeyFast, UserID: "bob", Value: buf.String()}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice finds nobody, and waits in a match of her own.
+	fast := `{"fast":true}`
+	matchID, reservation := findTestMatch(t, nk, "alice", fast)
+	d := matchtest.NewDriver(newTestHandler(), matchID, nk, matchtest.NewLogger(t.Logf))
+	if !d.Init(nk.Created[0].Params) {
+		t.Fatalf("match init failed with params %v", nk.Created[0].Params)
+	}
+	if ok, reason := d.Join(matchtest.NewPresence("alice"), map[string]string{"reservation": reservation}); !ok {
+		t.Fatalf("alice could not join her match: %v", reason)
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		payload string
+		// How long ago the user's search started, if it's longer than the requests so far.
+		waited time.Duration
+		// The match the user should be sent to, by the order it was created in.
+		match int
+		// Whether the user's search should go on.
+		searching bool
+	}{
+		{name: "rating too far from the waiting player's", userID: "bob", payload: fast, match: 2, searching: true},
+		{name: "searching again keeps the player's own match", userID: "bob", payload: fast, match: 2, searching: true},
+		{name: "waiting player isn't paired with themselves", userID: "alice", payload: fast, match: 1, searching: true},
+		{name: "different settings start a new search", userID: "alice", payload: `{"fast":true,"variant":"misere","board_size":4}`, match: 3, searching: true},
+		{name: "long enough search accepts any rating", userID: "bob", payload: fast, waited: 35 * time.Second, match: 1, searching: false},
+	}
+	searchKeys := map[string]string{
+		fast: matchPoolKey(moduleName, true, defaultBoardSize, defaultBoardSize, 0, standardVariant, minSeats, ""),
+		`{"fast":true,"variant":"misere","board_size":4}`: matchPoolKey(moduleName, true, 4, 4, 0, "misere", minSeats, ""),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.waited > 0 {
+				search := &api.MatchSearch{}
+				object := nk.StorageObject(matchSearchCollection, searchKeys[tt.payload], tt.userID)
+				if err := unmarshaler.Unmarshal(bytes.NewReader([]byte(object.GetValue())), search); err != nil {
+					t.Fatal(err)
+				}
+				search.StartTime = time.Now().Add(-tt.waited).Unix()
+				if err := writeMatchSearch(context.Background(), nk, marshaler, tt.userID, searchKeys[tt.payload], search); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			matchID, reservation = findTestMatch(t, nk, tt.userID, tt.payload)
+			if len(nk.Created) < tt.match || matchID != nk.Created[tt.match-1].MatchID {
+				t.Errorf("match = %v, want match %d of %v", matchID, tt.match, nk.Created)
+			}
+			// Only players who haven't joined the match yet need a seat held for them.
+			if joined := tt.userID == "alice" && tt.match == 1; (reservation == "") != joined {
+				t.Errorf("reservation = %q for a player joined %v", reservation, joined)
+			}
+			if searching := nk.StorageObject(matchSearchCollection, searchKeys[tt.payload], tt.userID) != nil; searching != tt.searching {
+				t.Errorf("searching = %v, want %v", searching, tt.searching)
+			}
+		})
+	}
+
+	if ok, reason := d.Join(matchtest.NewPresence("bob"), map[string]string{"reservation": reservation}); !ok {
+		t.Errorf("bob could not join the match he was paired in: %v", reason)
+	}
+}
+
+// Ask for a match with the given settings for the user, returning the match ID and seat reservation token, if any.
+func findTestMatch(t *testing.T, nk *matchtest.NakamaModule, userID, payload string) (string, string) {
+	t.Helper()
+	rpc := rpcFindMatch(&jsonpb.Marshaler{EnumsAsInts: true}, &jsonpb.Unmarshaler{})
+	out, err := rpc(userContext(userID), matchtest.NewLogger(t.Logf), nil, nk, payload)
+	if err != nil {
+		t.Fatalf("find match error: %v", err)
+	}
+	var resp struct {
+		MatchIds    []string `json:"match_ids"`
+		Reservation string   `json:"reservation"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if len(resp.MatchIds) != 1 {
+		t.Fatalf("response = %v, want one match", out)
+	}
+	return resp.MatchIds[0], resp.Reservation
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+// Number the next state message the match sends.
+func (s *MatchState) nextSequence() int64 {
+	s.sequence++
+	return s.sequence
+}
+
+// Build the full authoritative state of the match.
+func (s *MatchState) snapshot(t time.Time) *api.Snapshot {
+	snapshot := &api.Snapshot{
+		Sequence:     s.sequence,
+		Playing:      s.playing,
+		BoardSize:    int32(s.boardSize),
+		WinLength:    int32(s.winLength),
+		SeriesScore:  s.seriesScore,
+		SeriesLength: int32(s.seriesLength),
+		Variant:      s.variant,
+	}
+
+	if s.playing {
+		snapshot.Board = s.board
+		snapshot.Marks = s.marks
+		snapshot.Mark = s.mark
+		snapshot.Deadline = t.Add(time.Duration(s.deadlineRemainingTicks/tickRate) * time.Second).Unix()
+		snapshot.Clocks = s.clockMillis()
+		snapshot.Ultimate = s.ultimateBoard()
+		snapshot.Eliminated = s.eliminatedUserIDs()
+	} else if s.board != nil && s.marks != nil {
+		snapshot.Done = s.doneMessage(t)
+	}
+
+	return snapshot
+}
+
+// Answer clients asking for the full state of the match, and take their requests out of the messages left to handle.
+func (m *MatchHandler) answerSyncRequests(logger runtime.Logger, dispatcher runtime.MatchDispatcher, s *MatchState, messages []runtime.MatchData, t time.Time) []runtime.MatchData {
+	remaining := make([]runtime.MatchData, 0, len(messages))
+	var snapshot *api.Snapshot
+	for _, message := range messages {
+		if api.OpCode(message.GetOpCode()) != api.OpCode_OPCODE_SYNC_REQUEST {
+			remaining = append(remaining, message)
+			continue
+		}
+
+		if snapshot == nil {
+			snapshot = s.snapshot(t)
+		}
+		m.broadcast(logger, dispatcher, s, api.OpCode_OPCODE_SNAPSHOT, snapshot, []runtime.Presence{message})
+	}
+	return remaining
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const (
+	ultimateModuleName = "ultimate-tic-tac-toe"
+
+	// Cells along each side of a sub-board, and sub-boards along each side of the grid.
+	ultimateSubBoardSize = 3
+	// Cells in each sub-board, and sub-boards in the grid.
+	ultimateSubBoardCells = ultimateSubBoardSize * ultimateSubBoardSize
+	// Cells along each side of the whole board.
+	ultimateBoardSize = ultimateSubBoardSize * ultimateSubBoardSize
+
+	// The next move may be played in any sub-board still in play.
+	anySubBoard = -1
+)
+
+// Players place their own mark in the sub-board the previous move sent them to, and claiming a line of sub-boards wins.
+type ultimateRules struct {
+	standardRules
+}
+
+func (r *ultimateRules) allowed(s *MatchState, position int32) bool {
+	return s.ultimateLegalMove(position)
+}
+
+func (r *ultimateRules) result(s *MatchState, position int32, placed, playerMark api.Mark) (api.Mark, []int32, api.DoneReason, bool) {
+	if line, over := s.ultimateMoveResult(position, placed); line != nil {
+		return playerMark, line, api.DoneReason_DONE_REASON_WIN_LINE, true
+	} else if over {
+		return api.Mark_MARK_UNSPECIFIED, nil, api.DoneReason_DONE_REASON_BOARD_FULL, true
+	}
+	return api.Mark_MARK_UNSPECIFIED, nil, api.DoneReason_DONE_REASON_UNSPECIFIED, false
+}
+
+// Clear the sub-boards for a new game. The first move may go anywhere.
+func (s *MatchState) startUltimate() {
+	s.subBoardWinners = make([]api.Mark, ultimateSubBoardCells)
+	s.activeSubBoard = anySubBoard
+}
+
+// Whether a free position may be played, given the sub-board the previous move sent the player to.
+func (s *MatchState) ultimateLegalMove(position int32) bool {
+	subBoard := position / ultimateSubBoardCells
+	if s.subBoardClosed(subBoard) {
+		return false
+	}
+	return s.activeSubBoard == anySubBoard || s.activeSubBoard == subBoard
+}
+
+// Claim the sub-board if the move just played won it, and send the opponent to the sub-board matching the cell played.
+// Returns the sub-boards that won the game, if any, and whether every sub-board is now closed.
+func (s *MatchState) ultimateMoveResult(position int32, mark api.Mark) ([]int32, bool) {
+	subBoard := position / ultimateSubBoardCells
+	if findWinningLine(s.subBoard(subBoard), s.winningPositions, mark) != nil {
+		s.subBoardWinners[subBoard] = mark
+	}
+
+	s.activeSubBoard = position % ultimateSubBoardCells
+	if s.subBoardClosed(s.activeSubBoard) {
+		s.activeSubBoard = anySubBoard
+	}
+
+	if winningPosition := findWinningLine(s.subBoardWinners, s.winningPositions, mark); winningPosition != nil {
+		return winningPosition, true
+	}
+	for i := int32(0); i < ultimateSubBoardCells; i++ {
+		if !s.subBoardClosed(i) {
+			return nil, false
+		}
+	}
+	return nil, true
+}
+
+// Work out who claimed each sub-board from the cells, such as when picking up a saved game.
+func (s *MatchState) restoreSubBoardWinners() {
+	s.subBoardWinners = make([]api.Mark, ultimateSubBoardCells)
+	if len(s.board) != ultimateBoardSize*ultimateBoardSize {
+		return
+	}
+	for i := int32(0); i < ultimateSubBoardCells; i++ {
+		for _, mark := range []api.Mark{api.Mark_MARK_X, api.Mark_MARK_O} {
+			if findWinningLine(s.subBoard(i), s.winningPositions, mark) != nil {
+				s.subBoardWinners[i] = mark
+			}
+		}
+	}
+}
+
+// Build the nested view of the board for clients, or nil if the match isn't playing ultimate tic-tac-toe.
+func (s *MatchState) ultimateBoard() *api.UltimateBoard {
+	if !s.ultimate || len(s.board) != ultimateBoardSize*ultimateBoardSize {
+		return nil
+	}
+
+	board := &api.UltimateBoard{
+		SubBoards:      make([]*api.SubBoard, 0, ultimateSubBoardCells),
+		ActiveSubBoard: s.activeSubBoard,
+	}
+	for i := int32(0); i < ultimateSubBoardCells; i++ {
+		board.SubBoards = append(board.SubBoards, &api.SubBoard{
+			Cells:  s.subBoard(i),
+			Winner: s.subBoardWinners[i],
+			Closed: s.subBoardClosed(i),
+		})
+	}
+	return board
+}
+
+// The cells of one sub-board.
+func (s *MatchState) subBoard(i int32) []api.Mark {
+	return s.board[i*ultimateSubBoardCells : (i+1)*ultimateSubBoardCells]
+}
+
+// Whether a sub-board has been won or filled up.
+func (s *MatchState) subBoardClosed(i int32) bool {
+	return s.subBoardWinners[i] != api.Mark_MARK_UNSPECIFIED || len(freePositions(s.subBoard(i))) == 0
+}
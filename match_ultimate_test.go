@@ -0,0 +1,166 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func newUltimateTestMatch(t *testing.T, params map[string]interface{}) *matchtest.Driver {
+	t.Helper()
+	handler := newTestHandler()
+	handler.ultimate = true
+	return newTestMatchWithHandler(t, handler, params)
+}
+
+func TestUltimateMoves(t *testing.T) {
+	tests := []struct {
+		name string
+		// Valid moves played first, starting with X.
+		setup []int32
+		// Played by whoever's turn it is next.
+		move     int32
+		rejected bool
+		// The sub-board the following move must be played in, if the move is accepted.
+		active int32
+	}{
+		{
+			name:   "first move anywhere",
+			move:   40,
+			active: 4,
+		},
+		{
+			name:   "sent to the sub-board matching the cell",
+			setup:  []int32{40},
+			move:   36,
+			active: 0,
+		},
+		{
+			name:     "outside the sub-board sent to",
+			setup:    []int32{40},
+			move:     0,
+			rejected: true,
+		},
+		{
+			name:     "occupied cell",
+			setup:    []int32{40, 44},
+			move:     40,
+			rejected: true,
+		},
+		{
+			name:     "outside the board",
+			move:     81,
+			rejected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUltimateTestMatch(t, map[string]interface{}{})
+			x, o := startTestGame(t, d)
+			players := []*matchtest.Presence{x, o}
+			for i, position := range tt.setup {
+				d.Step(moveMessage(players[i%2], position))
+			}
+			if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) > 0 {
+				t.Fatal("setup move rejected")
+			}
+
+			d.Dispatcher.Reset()
+			d.Step(moveMessage(players[len(tt.setup)%2], tt.move))
+
+			rejected := len(d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED))) > 0
+			if rejected != tt.rejected {
+				t.Fatalf("rejected = %v, want %v", rejected, tt.rejected)
+			}
+			if tt.rejected {
+				return
+			}
+			update := &api.Update{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_UPDATE)), update)
+			if update.Ultimate == nil || update.Ultimate.ActiveSubBoard != tt.active {
+				t.Errorf("active sub-board = %v, want %v", update.Ultimate.GetActiveSubBoard(), tt.active)
+			}
+		})
+	}
+}
+
+func TestUltimateClosedSubBoardFreesChoice(t *testing.T) {
+	d := newUltimateTestMatch(t, map[string]interface{}{})
+	x, o := startTestGame(t, d)
+
+	// Sub-board 4 was claimed earlier in the game, so sending the opponent there lets them play anywhere.
+	s := testState(d)
+	s.subBoardWinners[4] = api.Mark_MARK_X
+	d.Step(moveMessage(x, 4))
+	if s.activeSubBoard != anySubBoard {
+		t.Fatalf("active sub-board = %v, want any", s.activeSubBoard)
+	}
+	d.Step(moveMessage(o, 80))
+	if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) > 0 {
+		t.Error("move in an open sub-board rejected")
+	}
+	d.Step(moveMessage(x, 39))
+	if rejected := d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED)); len(rejected) != 1 {
+		t.Error("move in a claimed sub-board accepted")
+	}
+}
+
+func TestUltimateWin(t *testing.T) {
+	d := newUltimateTestMatch(t, map[string]interface{}{})
+	x, _ := startTestGame(t, d)
+
+	// X has claimed the top left and top middle sub-boards, and is one move from claiming the top right.
+	s := testState(d)
+	for _, position := range []int32{0, 1, 2, 9, 10, 11, 18, 19} {
+		s.board[position] = api.Mark_MARK_X
+	}
+	s.subBoardWinners[0] = api.Mark_MARK_X
+	s.subBoardWinners[1] = api.Mark_MARK_X
+	s.activeSubBoard = 2
+	d.Step(moveMessage(x, 20))
+
+	done := &api.Done{}
+	decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)), done)
+	if done.Winner != api.Mark_MARK_X || done.Reason != api.DoneReason_DONE_REASON_WIN_LINE {
+		t.Errorf("done = (%v, %v), want (%v, %v)", done.Winner, done.Reason, api.Mark_MARK_X, api.DoneReason_DONE_REASON_WIN_LINE)
+	}
+	if !reflect.DeepEqual(done.WinnerPositions, []int32{0, 1, 2}) {
+		t.Errorf("winner positions = %v, want the top row of sub-boards", done.WinnerPositions)
+	}
+	if sub := done.GetUltimate().GetSubBoards(); len(sub) != ultimateSubBoardCells || sub[2].Winner != api.Mark_MARK_X || !sub[2].Closed {
+		t.Errorf("sub-boards = %v, want the top right claimed by X", sub)
+	}
+}
+
+func TestUltimateInit(t *testing.T) {
+	d := newUltimateTestMatch(t, map[string]interface{}{})
+	label := &MatchLabel{}
+	decodeTestLabel(t, d.Label, label)
+	if label.Ultimate != 1 || label.BoardSize != ultimateBoardSize || label.WinLength != ultimateSubBoardSize {
+		t.Errorf("label = %+v, want an ultimate match", label)
+	}
+
+	handler := newTestHandler()
+	handler.ultimate = true
+	d = matchtest.NewDriver(handler, testMatchID, matchtest.NewNakamaModule(), matchtest.NewLogger(nil))
+	if d.Init(map[string]interface{}{"fast": true, "board_size": 4}) {
+		t.Error("ultimate match accepted a board size")
+	}
+}
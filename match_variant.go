@@ -0,0 +1,103 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/heroiclabs/nakama-project-template/api"
+)
+
+const standardVariant = "standard"
+
+// Decides which moves are allowed and when a game is over. Each variant of the game has its own rules.
+type rules interface {
+	// The mark placed by a player holding playerMark who asked to place requested, unspecified if they didn't say.
+	// Reports false if the rules don't let them place it.
+	placedMark(playerMark, requested api.Mark) (api.Mark, bool)
+	// Whether a free cell may be played next.
+	allowed(s *MatchState, position int32) bool
+	// Check whether the move just played ended the game, and if so who won, the line that decided it, and why.
+	result(s *MatchState, position int32, placed, playerMark api.Mark) (winner api.Mark, winnerPositions []int32, reason api.DoneReason, over bool)
+}
+
+// The rules of each variant, by the name players choose it with.
+var variants = map[string]rules{
+	standardVariant: &standardRules{},
+	"misere":        &misereRules{},
+	"wild":          &wildRules{},
+	"notakto":       &notaktoRules{},
+}
+
+// Players place their own mark, and completing a line of it wins.
+type standardRules struct{}
+
+func (r *standardRules) placedMark(playerMark, requested api.Mark) (api.Mark, bool) {
+	return playerMark, requested == api.Mark_MARK_UNSPECIFIED || requested == playerMark
+}
+
+func (r *standardRules) allowed(s *MatchState, position int32) bool {
+	return true
+}
+
+func (r *standardRules) result(s *MatchState, position int32, placed, playerMark api.Mark) (api.Mark, []int32, api.DoneReason, bool) {
+	if line := findWinningLine(s.board, s.winningPositions, placed); line != nil {
+		return playerMark, line, api.DoneReason_DONE_REASON_WIN_LINE, true
+	}
+	return boardFullResult(s)
+}
+
+// Players place their own mark, and completing a line of it loses.
+type misereRules struct {
+	standardRules
+}
+
+func (r *misereRules) result(s *MatchState, position int32, placed, playerMark api.Mark) (api.Mark, []int32, api.DoneReason, bool) {
+	if line := findWinningLine(s.board, s.winningPositions, placed); line != nil {
+		return opponentMark(playerMark), line, api.DoneReason_DONE_REASON_LOSING_LINE, true
+	}
+	return boardFullResult(s)
+}
+
+// Players place either mark, and completing a line of either wins.
+type wildRules struct {
+	standardRules
+}
+
+func (r *wildRules) placedMark(playerMark, requested api.Mark) (api.Mark, bool) {
+	switch requested {
+	case api.Mark_MARK_UNSPECIFIED:
+		return playerMark, true
+	case api.Mark_MARK_X, api.Mark_MARK_O:
+		return requested, true
+	default:
+		return requested, false
+	}
+}
+
+// Both players place X, and completing a line loses. The marks players hold only decide whose turn it is.
+type notaktoRules struct {
+	misereRules
+}
+
+func (r *notaktoRules) placedMark(playerMark, requested api.Mark) (api.Mark, bool) {
+	return api.Mark_MARK_X, requested == api.Mark_MARK_UNSPECIFIED || requested == api.Mark_MARK_X
+}
+
+// A tie once no more moves can be played, otherwise the game goes on.
+func boardFullResult(s *MatchState) (api.Mark, []int32, api.DoneReason, bool) {
+	if len(s.legalPositions()) == 0 {
+		return api.Mark_MARK_UNSPECIFIED, nil, api.DoneReason_DONE_REASON_BOARD_FULL, true
+	}
+	return api.Mark_MARK_UNSPECIFIED, nil, api.DoneReason_DONE_REASON_UNSPECIFIED, false
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/heroiclabs/nakama-project-template/api"
+	"github.com/heroiclabs/nakama-project-template/matchtest"
+)
+
+func TestMatchVariants(t *testing.T) {
+	type move struct {
+		position int32
+		mark     api.Mark
+	}
+	tests := []struct {
+		name    string
+		variant string
+		// Played in turn, starting with X.
+		moves           []move
+		rejected        bool
+		winner          api.Mark
+		winnerPositions []int32
+		reason          api.DoneReason
+	}{
+		{
+			name:    "standard rejects the opponent's mark",
+			variant: standardVariant,
+			moves:   []move{{0, api.Mark_MARK_O}},
+			// The move is rejected and the game goes on.
+			rejected: true,
+		},
+		{
+			name:            "misere line by X loses",
+			variant:         "misere",
+			moves:           []move{{0, 0}, {3, 0}, {1, 0}, {4, 0}, {2, 0}},
+			winner:          api.Mark_MARK_O,
+			winnerPositions: []int32{0, 1, 2},
+			reason:          api.DoneReason_DONE_REASON_LOSING_LINE,
+		},
+		{
+			name:            "misere line by O loses",
+			variant:         "misere",
+			moves:           []move{{0, 0}, {3, 0}, {8, 0}, {4, 0}, {7, 0}, {5, 0}},
+			winner:          api.Mark_MARK_X,
+			winnerPositions: []int32{3, 4, 5},
+			reason:          api.DoneReason_DONE_REASON_LOSING_LINE,
+		},
+		{
+			name:            "wild line of either mark wins",
+			variant:         "wild",
+			moves:           []move{{0, api.Mark_MARK_O}, {4, api.Mark_MARK_X}, {1, api.Mark_MARK_O}, {8, api.Mark_MARK_X}, {2, api.Mark_MARK_O}},
+			winner:          api.Mark_MARK_X,
+			winnerPositions: []int32{0, 1, 2},
+			reason:          api.DoneReason_DONE_REASON_WIN_LINE,
+		},
+		{
+			name:     "wild rejects unknown marks",
+			variant:  "wild",
+			moves:    []move{{0, api.Mark(7)}},
+			rejected: true,
+		},
+		{
+			name:            "notakto shared line loses",
+			variant:         "notakto",
+			moves:           []move{{0, 0}, {1, api.Mark_MARK_X}, {2, 0}},
+			winner:          api.Mark_MARK_O,
+			winnerPositions: []int32{0, 1, 2},
+			reason:          api.DoneReason_DONE_REASON_LOSING_LINE,
+		},
+		{
+			name:     "notakto rejects O",
+			variant:  "notakto",
+			moves:    []move{{0, 0}, {1, api.Mark_MARK_O}},
+			rejected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestMatch(t, map[string]interface{}{"variant": tt.variant})
+			x, o := startTestGame(t, d)
+
+			players := []*matchtest.Presence{x, o}
+			for i, m := range tt.moves {
+				data := fmt.Sprintf(`{"position":%d,"mark":%d}`, m.position, m.mark)
+				d.Step(matchtest.NewMessage(players[i%2], int64(api.OpCode_OPCODE_MOVE), data))
+			}
+
+			rejected := len(d.Dispatcher.Sent(int64(api.OpCode_OPCODE_REJECTED))) > 0
+			if rejected != tt.rejected {
+				t.Fatalf("rejected = %v, want %v", rejected, tt.rejected)
+			}
+			if tt.rejected {
+				if !testState(d).playing {
+					t.Error("game ended after a rejected move")
+				}
+				return
+			}
+
+			msg := &api.Done{}
+			decodeTestMessage(t, d.Dispatcher.Last(int64(api.OpCode_OPCODE_DONE)), msg)
+			if msg.Winner != tt.winner || msg.Reason != tt.reason {
+				t.Errorf("done = (%v, %v), want (%v, %v)", msg.Winner, msg.Reason, tt.winner, tt.reason)
+			}
+			if !reflect.DeepEqual(msg.WinnerPositions, tt.winnerPositions) {
+				t.Errorf("winner positions = %v, want %v", msg.WinnerPositions, tt.winnerPositions)
+			}
+		})
+	}
+}
+
+func TestMatchVariantInit(t *testing.T) {
+	d := matchtest.NewDriver(newTestHandler(), testMatchID, matchtest.NewNakamaModule(), matchtest.NewLogger(nil))
+	if d.Init(map[string]interface{}{"fast": true, "variant": "reverse"}) {
+		t.Error("match accepted an unknown variant")
+	}
+
+	handler := newTestHandler()
+	handler.ultimate = true
+	d = matchtest.NewDriver(handler, testMatchID, matchtest.NewNakamaModule(), matchtest.NewLogger(nil))
+	if d.Init(map[string]interface{}{"fast": true, "variant": "misere"}) {
+		t.Error("ultimate match accepted a variant")
+	}
+}
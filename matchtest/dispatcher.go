@@ -0,0 +1,115 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchtest
+
+import (
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+// Compile-time check to make sure all required functions are implemented.
+var _ runtime.MatchDispatcher = &Dispatcher{}
+
+// A message the match sent to clients.
+type Broadcast struct {
+	Tick   int64
+	OpCode int64
+	Data   []byte
+	// Recipients of the message, nil if it went to everyone in the match.
+	Presences []runtime.Presence
+	Deferred  bool
+}
+
+// Sent to the presence, either directly or as part of a broadcast to everyone.
+func (b *Broadcast) SentTo(presence runtime.Presence) bool {
+	if b.Presences == nil {
+		return true
+	}
+	for _, p := range b.Presences {
+		if p.GetSessionId() == presence.GetSessionId() {
+			return true
+		}
+	}
+	return false
+}
+
+// Records everything the match sends, kicks, and advertises.
+type Dispatcher struct {
+	// The tick the match is currently processing, stamped on each broadcast.
+	Tick int64
+
+	Broadcasts []*Broadcast
+	Kicks      []runtime.Presence
+	Labels     []string
+
+	// Kicked presences the driver hasn't removed from the match yet.
+	pendingKicks []runtime.Presence
+}
+
+func (d *Dispatcher) BroadcastMessage(opCode int64, data []byte, presences []runtime.Presence, sender runtime.Presence, reliable bool) error {
+	d.Broadcasts = append(d.Broadcasts, &Broadcast{Tick: d.Tick, OpCode: opCode, Data: data, Presences: presences})
+	return nil
+}
+
+func (d *Dispatcher) BroadcastMessageDeferred(opCode int64, data []byte, presences []runtime.Presence, sender runtime.Presence, reliable bool) error {
+	d.Broadcasts = append(d.Broadcasts, &Broadcast{Tick: d.Tick, OpCode: opCode, Data: data, Presences: presences, Deferred: true})
+	return nil
+}
+
+func (d *Dispatcher) MatchKick(presences []runtime.Presence) error {
+	d.Kicks = append(d.Kicks, presences...)
+	d.pendingKicks = append(d.pendingKicks, presences...)
+	return nil
+}
+
+func (d *Dispatcher) MatchLabelUpdate(label string) error {
+	d.Labels = append(d.Labels, label)
+	return nil
+}
+
+// Every message sent so far with the given op code, oldest first.
+func (d *Dispatcher) Sent(opCode int64) []*Broadcast {
+	sent := make([]*Broadcast, 0)
+	for _, b := range d.Broadcasts {
+		if b.OpCode == opCode {
+			sent = append(sent, b)
+		}
+	}
+	return sent
+}
+
+// The most recent message sent with the given op code, or nil if there isn't one.
+func (d *Dispatcher) Last(opCode int64) *Broadcast {
+	for i := len(d.Broadcasts) - 1; i >= 0; i-- {
+		if d.Broadcasts[i].OpCode == opCode {
+			return d.Broadcasts[i]
+		}
+	}
+	return nil
+}
+
+// The label the match currently advertises, or empty if it has never updated the one it started with.
+func (d *Dispatcher) Label() string {
+	if len(d.Labels) == 0 {
+		return ""
+	}
+	return d.Labels[len(d.Labels)-1]
+}
+
+// Forget everything recorded so far, to focus on what the match does next.
+func (d *Dispatcher) Reset() {
+	d.Broadcasts = nil
+	d.Kicks = nil
+	d.Labels = nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchtest
+
+import (
+	"context"
+
+	"github.com/heroiclabs/nakama-common/api"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Runs a match handler the way the server would: one callback at a time, with the tick advancing on each loop. Kicked
+// presences leave the match before the next tick, just as they do on the server.
+type Driver struct {
+	Match      runtime.Match
+	Ctx        context.Context
+	Logger     *Logger
+	NK         *NakamaModule
+	Dispatcher *Dispatcher
+
+	// The match state returned by the last callback, nil once the match has ended.
+	State    interface{}
+	Tick     int64
+	TickRate int
+	// The label the match started with.
+	Label string
+
+	// Presences currently in the match, by session ID.
+	joined map[string]runtime.Presence
+}
+
+// Create a driver for the match, identified by the match ID in its context.
+func NewDriver(match runtime.Match, matchID string, nk *NakamaModule, logger *Logger) *Driver {
+	ctx := context.WithValue(context.Background(), runtime.RUNTIME_CTX_MATCH_ID, matchID)
+	ctx = context.WithValue(ctx, runtime.RUNTIME_CTX_NODE, "node")
+	return &Driver{
+		Match:      match,
+		Ctx:        ctx,
+		Logger:     logger,
+		NK:         nk,
+		Dispatcher: &Dispatcher{},
+		joined:     make(map[string]runtime.Presence),
+	}
+}
+
+// Start the match. Reports false if the handler refused the params.
+func (d *Driver) Init(params map[string]interface{}) bool {
+	d.State, d.TickRate, d.Label = d.Match.MatchInit(d.Ctx, d.Logger, nil, d.NK, params)
+	d.sync()
+	return d.State != nil
+}
+
+// Whether the match has ended.
+func (d *Driver) Closed() bool {
+	return d.State == nil
+}
+
+// Ask the match to let the presence in, without completing the join.
+func (d *Driver) JoinAttempt(presence runtime.Presence, metadata map[string]string) (bool, string) {
+	d.Dispatcher.Tick = d.Tick
+	state, accepted, reason := d.Match.MatchJoinAttempt(d.Ctx, d.Logger, nil, d.NK, d.Dispatcher, d.Tick, d.State, presence, metadata)
+	d.State = state
+	return accepted, reason
+}
+
+// Join the presence to the match if it's accepted. Reports the outcome of the join attempt.
+func (d *Driver) Join(presence runtime.Presence, metadata map[string]string) (bool, string) {
+	accepted, reason := d.JoinAttempt(presence, metadata)
+	if !accepted || d.Closed() {
+		return accepted, reason
+	}
+
+	d.joined[presence.GetSessionId()] = presence
+	d.State = d.Match.MatchJoin(d.Ctx, d.Logger, nil, d.NK, d.Dispatcher, d.Tick, d.State, []runtime.Presence{presence})
+	d.sync()
+	d.removeKicked()
+	return true, ""
+}
+
+// Disconnect the presences from the match.
+func (d *Driver) Leave(presences ...runtime.Presence) {
+	leaving := make([]runtime.Presence, 0, len(presences))
+	for _, presence := range presences {
+		if _, ok := d.joined[presence.GetSessionId()]; ok {
+			delete(d.joined, presence.GetSessionId())
+			leaving = append(leaving, presence)
+		}
+	}
+	if len(leaving) == 0 || d.Closed() {
+		return
+	}
+
+	d.Dispatcher.Tick = d.Tick
+	d.State = d.Match.MatchLeave(d.Ctx, d.Logger, nil, d.NK, d.Dispatcher, d.Tick, d.State, leaving)
+	d.sync()
+}
+
+// Run one tick of the match loop with the given input. Reports false if the match has ended.
+func (d *Driver) Step(messages ...runtime.MatchData) bool {
+	if d.Closed() {
+		return false
+	}
+
+	d.Dispatcher.Tick = d.Tick
+	d.State = d.Match.MatchLoop(d.Ctx, d.Logger, nil, d.NK, d.Dispatcher, d.Tick, d.State, messages)
+	d.Tick++
+	d.sync()
+	d.removeKicked()
+	return !d.Closed()
+}
+
+// Run n ticks of the match loop without input. Reports false if the match ended along the way.
+func (d *Driver) Steps(n int) bool {
+	for i := 0; i < n; i++ {
+		if !d.Step() {
+			return false
+		}
+	}
+	return true
+}
+
+// Run the match loop without input until the condition holds, for at most n ticks. Reports whether the condition
+// was met.
+func (d *Driver) StepUntil(n int, condition func() bool) bool {
+	for i := 0; i < n; i++ {
+		if condition() {
+			return true
+		}
+		if !d.Step() {
+			return condition()
+		}
+	}
+	return condition()
+}
+
+// Shut the match down as the server does on exit.
+func (d *Driver) Terminate(graceSeconds int) {
+	if d.Closed() {
+		return
+	}
+
+	d.Dispatcher.Tick = d.Tick
+	d.State = d.Match.MatchTerminate(d.Ctx, d.Logger, nil, d.NK, d.Dispatcher, d.Tick, d.State, graceSeconds)
+	d.sync()
+}
+
+// Presences currently joined to the match.
+func (d *Driver) Presences() []runtime.Presence {
+	presences := make([]runtime.Presence, 0, len(d.joined))
+	for _, presence := range d.joined {
+		presences = append(presences, presence)
+	}
+	return presences
+}
+
+func (d *Driver) removeKicked() {
+	kicked := d.Dispatcher.pendingKicks
+	d.Dispatcher.pendingKicks = nil
+	d.Leave(kicked...)
+}
+
+// Keep the module's record of the match up to date with its label and size, so matches can be listed and looked up
+// as they would be on the server. A match that has ended is forgotten.
+func (d *Driver) sync() {
+	matchID, _ := d.Ctx.Value(runtime.RUNTIME_CTX_MATCH_ID).(string)
+	if d.Closed() {
+		delete(d.NK.Matches, matchID)
+		return
+	}
+
+	match := d.NK.Matches[matchID]
+	if match == nil {
+		match = &api.Match{MatchId: matchID, Authoritative: true}
+		d.NK.Matches[matchID] = match
+	}
+	if label := d.Dispatcher.Label(); label != "" {
+		match.Label = &wrapperspb.StringValue{Value: label}
+	} else if match.Label == nil {
+		match.Label = &wrapperspb.StringValue{Value: d.Label}
+	}
+	match.Size = int32(len(d.joined))
+	match.TickRate = int32(d.TickRate)
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchtest
+
+import (
+	"fmt"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+// Compile-time check to make sure all required functions are implemented.
+var _ runtime.Logger = &Logger{}
+
+// A single logged line.
+type LogEntry struct {
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Records everything logged through it, and optionally echoes it to a test's output.
+type Logger struct {
+	entries *[]LogEntry
+	fields  map[string]interface{}
+	logf    func(format string, v ...interface{})
+}
+
+// Create a logger. Pass a test's Logf to see the log alongside test failures, or nil to only record.
+func NewLogger(logf func(format string, v ...interface{})) *Logger {
+	return &Logger{
+		entries: &[]LogEntry{},
+		fields:  map[string]interface{}{},
+		logf:    logf,
+	}
+}
+
+func (l *Logger) Debug(format string, v ...interface{}) { l.log("debug", format, v...) }
+func (l *Logger) Info(format string, v ...interface{})  { l.log("info", format, v...) }
+func (l *Logger) Warn(format string, v ...interface{})  { l.log("warn", format, v...) }
+func (l *Logger) Error(format string, v ...interface{}) { l.log("error", format, v...) }
+
+func (l *Logger) WithField(key string, v interface{}) runtime.Logger {
+	return l.WithFields(map[string]interface{}{key: v})
+}
+
+func (l *Logger) WithFields(fields map[string]interface{}) runtime.Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{entries: l.entries, fields: merged, logf: l.logf}
+}
+
+func (l *Logger) Fields() map[string]interface{} {
+	return l.fields
+}
+
+// Everything logged so far at the given level, or at every level if it's empty.
+func (l *Logger) Entries(level string) []LogEntry {
+	entries := make([]LogEntry, 0, len(*l.entries))
+	for _, entry := range *l.entries {
+		if level == "" || entry.Level == level {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (l *Logger) log(level, format string, v ...interface{}) {
+	entry := LogEntry{Level: level, Message: fmt.Sprintf(format, v...), Fields: l.fields}
+	*l.entries = append(*l.entries, entry)
+	if l.logf != nil {
+		l.logf("%v: %v", level, entry.Message)
+	}
+}
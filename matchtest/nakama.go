@@ -0,0 +1,385 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/heroiclabs/nakama-common/api"
+	"github.com/heroiclabs/nakama-common/runtime"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Returned when a storage write or delete names a version that doesn't match the stored object.
+var ErrStorageVersion = errors.New("storage version check failed")
+
+// A match created through the module, along with the parameters it was created with.
+type CreatedMatch struct {
+	MatchID string
+	Module  string
+	Params  map[string]interface{}
+}
+
+// An in-memory stand-in for the parts of the server API the match handlers use. Calling anything else panics, so a
+// test fails loudly when the handler starts depending on something new.
+type NakamaModule struct {
+	runtime.NakamaModule
+
+	// Mutual friends of each user, by user ID.
+	Friends map[string][]string
+	// Matches that exist, by match ID, including those created through MatchCreate.
+	Matches map[string]*api.Match
+
+	Created       []*CreatedMatch
+	Notifications []*runtime.NotificationSend
+
+	// Returned by StorageRead while set, to test how the handlers cope with storage failing.
+	StorageReadErr error
+
+	storage map[storageKey]*api.StorageObject
+	// Incremented on every write so each stored version is unique.
+	versions int
+}
+
+type storageKey struct {
+	collection string
+	key        string
+	userID     string
+}
+
+// Create an empty module.
+func NewNakamaModule() *NakamaModule {
+	return &NakamaModule{
+		Friends: make(map[string][]string),
+		Matches: make(map[string]*api.Match),
+		storage: make(map[storageKey]*api.StorageObject),
+	}
+}
+
+func (n *NakamaModule) StorageRead(ctx context.Context, reads []*runtime.StorageRead) ([]*api.StorageObject, error) {
+	if n.StorageReadErr != nil {
+		return nil, n.StorageReadErr
+	}
+	objects := make([]*api.StorageObject, 0, len(reads))
+	for _, read := range reads {
+		if object, ok := n.storage[storageKey{read.Collection, read.Key, read.UserID}]; ok {
+			objects = append(objects, copyObject(object))
+		}
+	}
+	return objects, nil
+}
+
+// Writes follow the server's rules for versions: "*" only creates an object that doesn't exist yet, and any other
+// non-empty version must match the stored one. Either all writes apply or none do.
+func (n *NakamaModule) StorageWrite(ctx context.Context, writes []*runtime.StorageWrite) ([]*api.StorageObjectAck, error) {
+	for _, write := range writes {
+		if err := n.checkVersion(storageKey{write.Collection, write.Key, write.UserID}, write.Version); err != nil {
+			return nil, err
+		}
+	}
+
+	acks := make([]*api.StorageObjectAck, 0, len(writes))
+	for _, write := range writes {
+		n.versions++
+		object := &api.StorageObject{
+			Collection:      write.Collection,
+			Key:             write.Key,
+			UserId:          write.UserID,
+			Value:           write.Value,
+			Version:         strconv.Itoa(n.versions),
+			PermissionRead:  int32(write.PermissionRead),
+			PermissionWrite: int32(write.PermissionWrite),
+		}
+		n.storage[storageKey{write.Collection, write.Key, write.UserID}] = object
+		acks = append(acks, &api.StorageObjectAck{
+			Collection: object.Collection,
+			Key:        object.Key,
+			Version:    object.Version,
+			UserId:     object.UserId,
+		})
+	}
+	return acks, nil
+}
+
+// Only storage writes are supported, and they follow the same rules as StorageWrite.
+func (n *NakamaModule) MultiUpdate(ctx context.Context, accountUpdates []*runtime.AccountUpdate, storageWrites []*runtime.StorageWrite, walletUpdates []*runtime.WalletUpdate, updateLedger bool) ([]*api.StorageObjectAck, []*runtime.WalletUpdateResult, error) {
+	if len(accountUpdates) > 0 || len(walletUpdates) > 0 {
+		panic("matchtest: MultiUpdate only supports storage writes")
+	}
+	acks, err := n.StorageWrite(ctx, storageWrites)
+	return acks, nil, err
+}
+
+func (n *NakamaModule) StorageDelete(ctx context.Context, deletes []*runtime.StorageDelete) error {
+	for _, d := range deletes {
+		if d.Version == "*" {
+			return ErrStorageVersion
+		}
+		if err := n.checkVersion(storageKey{d.Collection, d.Key, d.UserID}, d.Version); err != nil {
+			return err
+		}
+	}
+	for _, d := range deletes {
+		delete(n.storage, storageKey{d.Collection, d.Key, d.UserID})
+	}
+	return nil
+}
+
+// Objects are listed in key order. The cursor is the number of objects already returned.
+func (n *NakamaModule) StorageList(ctx context.Context, userID, collection string, limit int, cursor string) ([]*api.StorageObject, string, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+
+	objects := make([]*api.StorageObject, 0)
+	for key, object := range n.storage {
+		if key.collection == collection && key.userID == userID {
+			objects = append(objects, object)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	if offset > len(objects) {
+		offset = len(objects)
+	}
+	objects = objects[offset:]
+	next := ""
+	if limit > 0 && len(objects) > limit {
+		objects = objects[:limit]
+		next = strconv.Itoa(offset + limit)
+	}
+
+	page := make([]*api.StorageObject, 0, len(objects))
+	for _, object := range objects {
+		page = append(page, copyObject(object))
+	}
+	return page, next, nil
+}
+
+// A stored object, or nil if there isn't one. Lets tests inspect storage without going through the API.
+func (n *NakamaModule) StorageObject(collection, key, userID string) *api.StorageObject {
+	if object, ok := n.storage[storageKey{collection, key, userID}]; ok {
+		return copyObject(object)
+	}
+	return nil
+}
+
+// Created matches aren't started. Tests that need them running create a driver for them from the recorded params.
+func (n *NakamaModule) MatchCreate(ctx context.Context, module string, params map[string]interface{}) (string, error) {
+	matchID := fmt.Sprintf("match-%d.node", len(n.Created)+1)
+	n.Created = append(n.Created, &CreatedMatch{MatchID: matchID, Module: module, Params: params})
+	n.Matches[matchID] = &api.Match{MatchId: matchID, Authoritative: true}
+	return matchID, nil
+}
+
+func (n *NakamaModule) MatchGet(ctx context.Context, id string) (*api.Match, error) {
+	return n.Matches[id], nil
+}
+
+// Lists matches in match ID order. The query understands the clauses the match handlers use: required (+) and
+// excluded (-) label fields, matched exactly or by a >=, <=, > or < bound on a number. Clauses without a prefix only
+// affect the ordering on the server, so they're ignored.
+func (n *NakamaModule) MatchList(ctx context.Context, limit int, authoritative bool, label string, minSize, maxSize *int, query string) ([]*api.Match, error) {
+	ids := make([]string, 0, len(n.Matches))
+	for id := range n.Matches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	matches := make([]*api.Match, 0)
+	for _, id := range ids {
+		match := n.Matches[id]
+		switch {
+		case match.GetAuthoritative() != authoritative:
+		case label != "" && match.GetLabel().GetValue() != label:
+		case minSize != nil && int(match.GetSize()) < *minSize:
+		case maxSize != nil && int(match.GetSize()) > *maxSize:
+		case query != "" && !matchQuery(match.GetLabel().GetValue(), query):
+		default:
+			matches = append(matches, match)
+		}
+		if limit > 0 && len(matches) == limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+func (n *NakamaModule) NotificationSend(ctx context.Context, userID, subject string, content map[string]interface{}, code int, sender string, persistent bool) error {
+	return n.NotificationsSend(ctx, []*runtime.NotificationSend{{
+		UserID:     userID,
+		Subject:    subject,
+		Content:    content,
+		Code:       code,
+		Sender:     sender,
+		Persistent: persistent,
+	}})
+}
+
+func (n *NakamaModule) NotificationsSend(ctx context.Context, notifications []*runtime.NotificationSend) error {
+	n.Notifications = append(n.Notifications, notifications...)
+	return nil
+}
+
+// Every user exists, with their user ID as their username.
+func (n *NakamaModule) UsersGetId(ctx context.Context, userIDs []string, facebookIDs []string) ([]*api.User, error) {
+	users := make([]*api.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, &api.User{Id: id, Username: id})
+	}
+	return users, nil
+}
+
+// Tokens name the user they were generated for, and keep the expiry they were asked for.
+func (n *NakamaModule) AuthenticateTokenGenerate(userID, username string, exp int64, vars map[string]string) (string, int64, error) {
+	return "token-" + userID, exp, nil
+}
+
+// All friendships are mutual, so listing any other state returns nothing. The cursor is the number of friends already
+// returned.
+func (n *NakamaModule) FriendsList(ctx context.Context, userID string, limit int, state *int, cursor string) ([]*api.Friend, string, error) {
+	if state != nil && *state != 0 {
+		return []*api.Friend{}, "", nil
+	}
+
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+
+	ids := n.Friends[userID]
+	if offset > len(ids) {
+		offset = len(ids)
+	}
+	ids = ids[offset:]
+	next := ""
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+		next = strconv.Itoa(offset + limit)
+	}
+
+	friends := make([]*api.Friend, 0, len(ids))
+	for _, id := range ids {
+		friends = append(friends, &api.Friend{
+			User:  &api.User{Id: id, Username: id},
+			State: &wrapperspb.Int32Value{Value: 0},
+		})
+	}
+	return friends, next, nil
+}
+
+// Make two users mutual friends.
+func (n *NakamaModule) AddFriends(userID, friendID string) {
+	n.Friends[userID] = append(n.Friends[userID], friendID)
+	n.Friends[friendID] = append(n.Friends[friendID], userID)
+}
+
+func (n *NakamaModule) checkVersion(key storageKey, version string) error {
+	object, exists := n.storage[key]
+	switch {
+	case version == "":
+		return nil
+	case version == "*":
+		if exists {
+			return ErrStorageVersion
+		}
+	case !exists || object.Version != version:
+		return ErrStorageVersion
+	}
+	return nil
+}
+
+func copyObject(object *api.StorageObject) *api.StorageObject {
+	return &api.StorageObject{
+		Collection:      object.Collection,
+		Key:             object.Key,
+		UserId:          object.UserId,
+		Value:           object.Value,
+		Version:         object.Version,
+		PermissionRead:  object.PermissionRead,
+		PermissionWrite: object.PermissionWrite,
+	}
+}
+
+// Whether a JSON match label satisfies every required and excluded clause of the query.
+func matchQuery(label, query string) bool {
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(label), &fields); err != nil {
+		return false
+	}
+
+	for _, clause := range strings.Fields(query) {
+		want := true
+		switch clause[0] {
+		case '+':
+			clause = clause[1:]
+		case '-':
+			want = false
+			clause = clause[1:]
+		default:
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(clause, "label."), ":", 2)
+		if len(parts) != 2 {
+			return false
+		}
+		if matchClause(fields[parts[0]], strings.Trim(parts[1], `"`)) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether a label field matches a clause value, exactly or within a numeric bound.
+func matchClause(field interface{}, value string) bool {
+	if field == nil {
+		return false
+	}
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(value, op) {
+			continue
+		}
+		number, ok := field.(float64)
+		bound, err := strconv.ParseFloat(strings.TrimPrefix(value, op), 64)
+		if !ok || err != nil {
+			return false
+		}
+		switch op {
+		case ">=":
+			return number >= bound
+		case "<=":
+			return number <= bound
+		case ">":
+			return number > bound
+		default:
+			return number < bound
+		}
+	}
+	return fmt.Sprint(field) == value
+}
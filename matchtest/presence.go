@@ -0,0 +1,101 @@
+// Copyright 2020 The Nakama Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package matchtest runs authoritative match handlers in-process, without a Nakama server, so their behaviour can be
+// tested tick by tick.
+package matchtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+)
+
+// Compile-time checks to make sure the fakes can stand in for the real thing.
+var (
+	_ runtime.Presence        = &Presence{}
+	_ runtime.MatchData       = &Message{}
+	_ runtime.MatchmakerEntry = &MatchmakerEntry{}
+)
+
+// A user's connection to the match.
+type Presence struct {
+	UserID    string
+	SessionID string
+	Username  string
+	NodeID    string
+}
+
+// Create a presence for a user, with a session ID that's unique to this call.
+func NewPresence(userID string) *Presence {
+	sessionCount++
+	return &Presence{
+		UserID:    userID,
+		SessionID: fmt.Sprintf("%v-session-%d", userID, sessionCount),
+		Username:  userID,
+		NodeID:    "node",
+	}
+}
+
+var sessionCount int
+
+// Open another session for the same user, as if they'd connected again from elsewhere.
+func (p *Presence) Reconnect() *Presence {
+	return NewPresence(p.UserID)
+}
+
+func (p *Presence) GetHidden() bool      { return false }
+func (p *Presence) GetPersistence() bool { return false }
+func (p *Presence) GetUsername() string  { return p.Username }
+func (p *Presence) GetStatus() string    { return "" }
+func (p *Presence) GetUserId() string    { return p.UserID }
+func (p *Presence) GetSessionId() string { return p.SessionID }
+func (p *Presence) GetNodeId() string    { return p.NodeID }
+
+// Input sent to the match by a client.
+type Message struct {
+	*Presence
+	OpCode int64
+	Data   []byte
+}
+
+// Create a message from the presence with the given op code and payload.
+func NewMessage(p *Presence, opCode int64, data string) *Message {
+	return &Message{Presence: p, OpCode: opCode, Data: []byte(data)}
+}
+
+func (m *Message) GetOpCode() int64  { return m.OpCode }
+func (m *Message) GetData() []byte   { return m.Data }
+func (m *Message) GetReliable() bool { return true }
+func (m *Message) GetReceiveTime() int64 {
+	return time.Now().UTC().UnixNano() / int64(time.Millisecond)
+}
+
+// A user paired by the matchmaker, with the properties of the ticket they submitted.
+type MatchmakerEntry struct {
+	*Presence
+	Ticket     string
+	Properties map[string]interface{}
+}
+
+// Create a matchmaker entry for the presence. Numeric properties should be float64, as the server delivers them.
+func NewMatchmakerEntry(p *Presence, properties map[string]interface{}) *MatchmakerEntry {
+	return &MatchmakerEntry{Presence: p, Ticket: p.SessionID + "-ticket", Properties: properties}
+}
+
+func (e *MatchmakerEntry) GetPresence() runtime.Presence         { return e.Presence }
+func (e *MatchmakerEntry) GetTicket() string                     { return e.Ticket }
+func (e *MatchmakerEntry) GetProperties() map[string]interface{} { return e.Properties }
+func (e *MatchmakerEntry) GetPartyId() string                    { return "" }